@@ -13,20 +13,27 @@ import (
 	"github.com/aeilang/urlshortener/internal/api"
 	"github.com/aeilang/urlshortener/internal/cache"
 	"github.com/aeilang/urlshortener/internal/service"
+	"github.com/aeilang/urlshortener/pkg/domainverify"
 	"github.com/aeilang/urlshortener/pkg/emailsender"
+	"github.com/aeilang/urlshortener/pkg/geoip"
 	"github.com/aeilang/urlshortener/pkg/hasher"
 	"github.com/aeilang/urlshortener/pkg/jwt"
 	"github.com/aeilang/urlshortener/pkg/logger"
+	"github.com/aeilang/urlshortener/pkg/qrcode"
 	"github.com/aeilang/urlshortener/pkg/randnum"
 	"github.com/aeilang/urlshortener/pkg/shortcode"
+	"github.com/aeilang/urlshortener/pkg/urlnorm"
+	"github.com/aeilang/urlshortener/pkg/useragent"
 	"github.com/aeilang/urlshortener/pkg/validator"
 	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
 )
 
 type Application struct {
 	e           *echo.Echo
 	db          *sql.DB
 	redisClient *cache.RedisCache
+	geoIP       *geoip.GeoIP
 	urlService  *service.URLService
 	urlHandler  *api.URLHandler
 	userHandler *api.UserHandler
@@ -62,6 +69,11 @@ func InitApp(filePath string) (*Application, error) {
 		return nil, err
 	}
 
+	geoIP, err := geoip.NewGeoIP(cfg.GeoIP)
+	if err != nil {
+		return nil, err
+	}
+
 	passwordHash := hasher.NewPasswordHash()
 
 	jwt := jwt.NewJWT(cfg.JWT)
@@ -70,23 +82,41 @@ func InitApp(filePath string) (*Application, error) {
 
 	shortCode := shortcode.NewShortCode(cfg.ShortCode)
 
+	userAgentParser := useragent.NewParser(cfg.Bot)
+
+	qrcodeGenerator, err := qrcode.NewGenerator(cfg.QRCode)
+	if err != nil {
+		return nil, err
+	}
+
+	domainVerifier := domainverify.NewVerifier(cfg.Domain, domainverify.NewResolver(cfg.Domain))
+
+	urlNormalizer := urlnorm.NewNormalizer(cfg.Normalize)
+
 	customValidator := validator.NewCustomValidator()
 
 	// service
-	urlService := service.NewURLService(db, shortCode, redisClient, cfg.App)
+	urlService := service.NewURLService(db, shortCode, redisClient, geoIP, userAgentParser, passwordHash, qrcodeGenerator, domainVerifier, urlNormalizer, cfg.App)
 	userService := service.NewUserService(db, passwordHash, jwt, redisClient, emailSender, randNum)
 
 	// handler
-	urlHandler := api.NewURLHandler(urlService)
+	urlHandler, err := api.NewURLHandler(urlService, cfg.App)
+	if err != nil {
+		return nil, err
+	}
 	userHandler := api.NewUserHandler(userService)
 
 	// echo
-	e := NewEcho(cfg.Server, customValidator)
+	e, err := NewEcho(cfg.Server, customValidator)
+	if err != nil {
+		return nil, err
+	}
 
 	a := &Application{
 		e:           e,
 		db:          db,
 		redisClient: redisClient,
+		geoIP:       geoIP,
 		urlService:  urlService,
 		urlHandler:  urlHandler,
 		userHandler: userHandler,
@@ -100,6 +130,8 @@ func InitApp(filePath string) (*Application, error) {
 
 func (a *Application) Start() {
 	go a.syncViewsToDB()
+	go a.purgeTrash()
+	go a.backfillURLHashes()
 
 	go func() {
 		if err := a.e.Start(a.cfg.Server.Addr); err != nil {
@@ -128,6 +160,42 @@ func (a *Application) syncViewsToDB() {
 
 }
 
+// purgeTrash 定期永久删除回收站中超过保留期限的短链接，未配置清理间隔时不清理
+func (a *Application) purgeTrash() {
+	if a.cfg.App.TrashPurgeInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(a.cfg.App.TrashPurgeInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+
+			n, err := a.urlService.PurgeTrash(ctx)
+			if err != nil {
+				logger.Error(err.Error())
+			}
+			if n > 0 {
+				logger.Info("回收站清理完成", zap.Int("purged", n))
+			}
+		}()
+	}
+}
+
+// backfillURLHashes 启动时为迁移前创建的短链接回填url_hash，之后新建的短链接都会写入
+func (a *Application) backfillURLHashes() {
+	n, err := a.urlService.BackfillURLHashes(context.Background())
+	if err != nil {
+		logger.Error(err.Error())
+	}
+	if n > 0 {
+		logger.Info("url_hash回填完成", zap.Int("filled", n))
+	}
+}
+
 // gracefully shutdown
 func (a *Application) shutdown() {
 	defer func() {
@@ -140,6 +208,11 @@ func (a *Application) shutdown() {
 			logger.Error(err.Error())
 		}
 	}()
+	defer func() {
+		if err := a.geoIP.Close(); err != nil {
+			logger.Error(err.Error())
+		}
+	}()
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
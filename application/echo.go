@@ -3,6 +3,10 @@
 package application
 
 import (
+	"fmt"
+	"net"
+	"strings"
+
 	"github.com/aeilang/urlshortener/config"
 	"github.com/aeilang/urlshortener/internal/mw"
 	"github.com/labstack/echo/v4"
@@ -11,7 +15,7 @@ import (
 )
 
 // NewEcho 创建并配置一个新的Echo实例
-func NewEcho(cfg config.ServerConfig, validator echo.Validator) *echo.Echo {
+func NewEcho(cfg config.ServerConfig, validator echo.Validator) (*echo.Echo, error) {
 	e := echo.New()
 
 	// 基本配置
@@ -20,6 +24,13 @@ func NewEcho(cfg config.ServerConfig, validator echo.Validator) *echo.Echo {
 	e.Server.WriteTimeout = cfg.WriteTimeout  // 设置写超时
 	e.Server.ReadTimeout = cfg.ReadTimeout    // 设置读超时
 
+	// 设置获取客户端IP的方式
+	ipExtractor, err := newIPExtractor(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	e.IPExtractor = ipExtractor
+
 	// 设置请求参数验证器
 	e.Validator = validator
 
@@ -31,5 +42,35 @@ func NewEcho(cfg config.ServerConfig, validator echo.Validator) *echo.Echo {
 	// 添加Swagger文档路由
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
-	return e
+	return e, nil
+}
+
+// newIPExtractor 没有配置反向代理时直接使用连接的IP，
+// 否则只信任来自这些代理的X-Forwarded-For
+func newIPExtractor(proxies []string) (echo.IPExtractor, error) {
+	if len(proxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil && ip.To4() != nil {
+				proxy += "/32"
+			} else {
+				proxy += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("不正确的反向代理地址: %s", proxy)
+		}
+		options = append(options, echo.TrustIPRange(ipNet))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...), nil
 }
@@ -18,13 +18,47 @@ func (a *Application) initRouter() {
 	u.GET("/register/:email", a.userHandler.SendEmailCode) // 发送注册验证码
 
 	// URL缩短服务相关路由
-	a.e.GET("/:code", a.urlHandler.RedirectURL) // 短链接重定向
+	a.e.GET("/:code", a.urlHandler.RedirectURL)        // 短链接重定向
+	a.e.HEAD("/:code", a.urlHandler.RedirectURL)       // HEAD请求同样重定向，但按机器人统计
+	a.e.POST("/:code", a.urlHandler.UnlockURL)         // 提交密码访问受保护的短链接
+	a.e.GET("/:code/preview", a.urlHandler.PreviewURL) // 预览短链接，不重定向
 
 	// 需要JWT认证的URL管理API
 	url := a.e.Group("/api", mw.JWTAuther(a.jwt))
-	url.POST("/url", a.urlHandler.CreateURL)                // 创建短链接
-	url.GET("/urls", a.urlHandler.GetURLs)                  // 获取用户的所有短链接
-	url.DELETE("/url/:code", a.urlHandler.DeleteURL)        // 删除短链接
-	url.PATCH("/url/:code", a.urlHandler.UpdateURLDuration) // 更新短链接的有效期
+	url.POST("/url", a.urlHandler.CreateURL)                              // 创建短链接
+	url.GET("/urls", a.urlHandler.GetURLs)                                // 获取用户的所有短链接
+	url.POST("/urls/bulk", a.urlHandler.BulkCreateURLs)                   // 批量创建短链接
+	url.GET("/urls/export", a.urlHandler.ExportURLs)                      // 导出短链接
+	url.POST("/urls/batch", a.urlHandler.BatchURLs)                       // 批量操作短链接
+	url.DELETE("/url/:code", a.urlHandler.DeleteURL)                      // 将短链接移入回收站
+	url.PATCH("/url/:code", a.urlHandler.UpdateURL)                       // 修改短链接
+	url.GET("/url/:code/history", a.urlHandler.GetHistory)                // 获取短链接的历史版本
+	url.POST("/url/:code/history/:id/rollback", a.urlHandler.RollbackURL) // 回滚到指定的历史版本
+	url.GET("/url/:code/stats", a.urlHandler.GetStats)                    // 获取短链接的点击统计
+	url.GET("/url/:code/stats/geo", a.urlHandler.GetGeoStats)             // 获取点击的地理分布
+	url.GET("/url/:code/stats/devices", a.urlHandler.GetDeviceStats)      // 获取点击的设备分布
+	url.GET("/stats/devices", a.urlHandler.GetUserDeviceStats)            // 获取用户所有短链接的设备分布
+	url.GET("/url/:code/rules", a.urlHandler.GetRules)                    // 获取短链接的定向规则
+	url.POST("/url/:code/rules", a.urlHandler.CreateRule)                 // 添加定向规则
+	url.PUT("/url/:code/rules/:id", a.urlHandler.UpdateRule)              // 修改定向规则
+	url.DELETE("/url/:code/rules/:id", a.urlHandler.DeleteRule)           // 删除定向规则
+	url.GET("/url/:code/variants", a.urlHandler.GetVariants)              // 获取短链接的A/B版本
+	url.POST("/url/:code/variants", a.urlHandler.CreateVariant)           // 添加A/B版本
+	url.PUT("/url/:code/variants/:id", a.urlHandler.UpdateVariant)        // 修改A/B版本
+	url.DELETE("/url/:code/variants/:id", a.urlHandler.DeleteVariant)     // 删除A/B版本
+	url.GET("/url/:code/qr", a.urlHandler.GetQRCode)                      // 生成短链接的二维码
+	url.PUT("/url/:code/tags/:id", a.urlHandler.AttachTag)                // 为短链接添加标签
+	url.DELETE("/url/:code/tags/:id", a.urlHandler.DetachTag)             // 移除短链接的标签
+	url.GET("/trash", a.urlHandler.GetTrash)                              // 获取回收站中的短链接
+	url.POST("/trash/:code/restore", a.urlHandler.RestoreURL)             // 从回收站恢复短链接
+	url.DELETE("/trash/:code", a.urlHandler.PurgeURL)                     // 永久删除回收站中的短链接
+	url.GET("/domains", a.urlHandler.GetDomains)                          // 获取用户的自定义域名
+	url.POST("/domains", a.urlHandler.CreateDomain)                       // 添加自定义域名
+	url.POST("/domains/:id/verify", a.urlHandler.VerifyDomain)            // 验证自定义域名的DNS记录
+	url.DELETE("/domains/:id", a.urlHandler.DeleteDomain)                 // 删除自定义域名
+	url.GET("/tags", a.urlHandler.GetTags)                                // 获取用户的标签
+	url.POST("/tags", a.urlHandler.CreateTag)                             // 创建标签
+	url.PATCH("/tags/:id", a.urlHandler.RenameTag)                        // 重命名标签
+	url.DELETE("/tags/:id", a.urlHandler.DeleteTag)                       // 删除标签
 
 }
@@ -18,6 +18,11 @@ type Config struct {
 	Email     EmailConfig     `mapstructure:"email"`
 	JWT       JWTConfig       `mapstructure:"jwt"`
 	RandNum   RandNumConfig   `mapstructure:"rand_num"`
+	GeoIP     GeoIPConfig     `mapstructure:"geoip"`
+	Bot       BotConfig       `mapstructure:"bot"`
+	QRCode    QRCodeConfig    `mapstructure:"qrcode"`
+	Domain    DomainConfig    `mapstructure:"domain"`
+	Normalize NormalizeConfig `mapstructure:"normalize"`
 }
 
 var Cfg *Config
@@ -54,7 +59,8 @@ type DatabaseConfig struct {
 }
 
 func (d DatabaseConfig) DSN() string {
-	return fmt.Sprintf("%s://%s:%s@%s:%d/%s?sslmode=%s", d.Driver, d.User, d.Password, d.Host, d.Port, d.DBName, d.SSLMode)
+	// 时间列不带时区，会话统一使用UTC，CURRENT_TIMESTAMP与应用写入的时间可以直接比较
+	return fmt.Sprintf("%s://%s:%s@%s:%d/%s?sslmode=%s&timezone=UTC", d.Driver, d.User, d.Password, d.Host, d.Port, d.DBName, d.SSLMode)
 }
 
 type LogConfig struct {
@@ -71,11 +77,15 @@ type JWTConfig struct {
 }
 
 type RedisConfig struct {
-	Address           string        `mapstructure:"address"`
-	Password          string        `mapstructure:"password"`
-	DB                int           `mapstructure:"db"`
-	UrlDuration       time.Duration `mapstructure:"url_duration"`
-	EmailCodeDuration time.Duration `mapstructure:"email_code_duration"`
+	Address                 string        `mapstructure:"address"`
+	Password                string        `mapstructure:"password"`
+	DB                      int           `mapstructure:"db"`
+	UrlDuration             time.Duration `mapstructure:"url_duration"`
+	EmailCodeDuration       time.Duration `mapstructure:"email_code_duration"`
+	VisitorDuration         time.Duration `mapstructure:"visitor_duration"`
+	PasswordAttemptDuration time.Duration `mapstructure:"password_attempt_duration"`
+	QRCodeDuration          time.Duration `mapstructure:"qrcode_duration"`
+	URLCountDuration        time.Duration `mapstructure:"url_count_duration"`
 }
 
 type EmailConfig struct {
@@ -88,15 +98,43 @@ type EmailConfig struct {
 }
 
 type ServerConfig struct {
-	Addr         string        `mapstructure:"addr"`
-	WriteTimeout time.Duration `mapstructure:"write_timeout"`
-	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
+	Addr           string        `mapstructure:"addr"`
+	WriteTimeout   time.Duration `mapstructure:"write_timeout"`
+	ReadTimeout    time.Duration `mapstructure:"read_timeout"`
+	TrustedProxies []string      `mapstructure:"trusted_proxies"`
 }
 
 type AppConfig struct {
-	BaseURL          string        `mapstructure:"base_url"`
-	DefaultDuration  time.Duration `mapstructure:"default_duration"`
-	SyncViewDuration time.Duration `mapstructure:"sync_view_duration"`
+	BaseURL             string        `mapstructure:"base_url"`
+	DefaultDuration     time.Duration `mapstructure:"default_duration"`
+	SyncViewDuration    time.Duration `mapstructure:"sync_view_duration"`
+	PasswordMaxAttempts int           `mapstructure:"password_max_attempts"`
+	NotActivePage       string        `mapstructure:"not_active_page"`
+	RedirectType        string        `mapstructure:"redirect_type"`
+	TrashRetention      time.Duration `mapstructure:"trash_retention"`
+	TrashPurgeInterval  time.Duration `mapstructure:"trash_purge_interval"`
+}
+
+type GeoIPConfig struct {
+	DBPath string `mapstructure:"db_path"`
+}
+
+type BotConfig struct {
+	Signatures []string `mapstructure:"signatures"`
+}
+
+type QRCodeConfig struct {
+	LogoPath string `mapstructure:"logo_path"`
+}
+
+type DomainConfig struct {
+	RecordPrefix string `mapstructure:"record_prefix"`
+	DNSServer    string `mapstructure:"dns_server"`
+}
+
+type NormalizeConfig struct {
+	StripParams []string `mapstructure:"strip_params"`
+	SortParams  bool     `mapstructure:"sort_params"`
 }
 
 type ShortCodeConfig struct {
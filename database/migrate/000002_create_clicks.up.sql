@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS clicks (
+    "id" BIGSERIAL PRIMARY KEY,
+    "url_id" BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    "destination" TEXT NOT NULL, -- 本次点击实际跳转的地址
+    "referrer" TEXT NOT NULL DEFAULT '',
+    "user_agent" TEXT NOT NULL DEFAULT '',
+    "ip" TEXT NOT NULL DEFAULT '',
+    "clicked_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_clicks_url_id_clicked_at ON clicks(url_id, clicked_at); -- 按链接和时间范围查询点击
@@ -0,0 +1,3 @@
+ALTER TABLE clicks
+    DROP COLUMN IF EXISTS "country",
+    DROP COLUMN IF EXISTS "city";
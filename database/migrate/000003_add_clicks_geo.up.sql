@@ -0,0 +1,3 @@
+ALTER TABLE clicks
+    ADD COLUMN "country" TEXT NOT NULL DEFAULT '', -- ISO 3166 国家代码
+    ADD COLUMN "city" TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+ALTER TABLE clicks
+    DROP COLUMN IF EXISTS "device",
+    DROP COLUMN IF EXISTS "os",
+    DROP COLUMN IF EXISTS "browser",
+    DROP COLUMN IF EXISTS "is_bot";
@@ -0,0 +1,5 @@
+ALTER TABLE clicks
+    ADD COLUMN "device" TEXT NOT NULL DEFAULT '', -- desktop/mobile/tablet/bot/unknown
+    ADD COLUMN "os" TEXT NOT NULL DEFAULT '',
+    ADD COLUMN "browser" TEXT NOT NULL DEFAULT '',
+    ADD COLUMN "is_bot" BOOLEAN NOT NULL DEFAULT FALSE;
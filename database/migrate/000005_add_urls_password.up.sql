@@ -0,0 +1,2 @@
+ALTER TABLE urls
+    ADD COLUMN "password_hash" TEXT NOT NULL DEFAULT ''; -- 为空表示不需要密码
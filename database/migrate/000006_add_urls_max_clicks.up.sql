@@ -0,0 +1,3 @@
+ALTER TABLE urls
+    ADD COLUMN "max_clicks" INT, -- 为空表示不限制重定向次数
+    ADD COLUMN "redirect_count" INT NOT NULL DEFAULT 0; -- 仅在限制次数时由数据库原子累加
@@ -0,0 +1,2 @@
+ALTER TABLE urls
+    ADD COLUMN "active_from" TIMESTAMP; -- 为空表示创建后立即生效
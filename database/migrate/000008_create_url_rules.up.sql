@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS url_rules (
+    "id" BIGSERIAL PRIMARY KEY,
+    "url_id" BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    "position" INT NOT NULL DEFAULT 0, -- 按从小到大的顺序匹配，命中第一条即停止
+    "condition_type" TEXT NOT NULL, -- country/os/device/language/weekday/time
+    "condition_value" TEXT NOT NULL,
+    "timezone" TEXT NOT NULL DEFAULT 'UTC', -- weekday和time条件使用的时区
+    "destination" TEXT NOT NULL,
+    "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_url_rules_url_id_position ON url_rules(url_id, position);
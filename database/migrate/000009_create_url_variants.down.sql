@@ -0,0 +1,4 @@
+ALTER TABLE clicks
+    DROP COLUMN IF EXISTS "variant_id";
+
+DROP TABLE IF EXISTS url_variants;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS url_variants (
+    "id" BIGSERIAL PRIMARY KEY,
+    "url_id" BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    "destination" TEXT NOT NULL,
+    "weight" INT NOT NULL CHECK (weight > 0), -- 按权重比例分配访客
+    "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_url_variants_url_id ON url_variants(url_id);
+
+ALTER TABLE clicks
+    ADD COLUMN "variant_id" BIGINT REFERENCES url_variants(id) ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE urls
+    ADD COLUMN "redirect_type" TEXT NOT NULL DEFAULT ''; -- 301/302/307/308/meta, 为空时使用全局默认值
@@ -0,0 +1,2 @@
+ALTER TABLE urls
+    ADD COLUMN "always_preview" BOOLEAN NOT NULL DEFAULT FALSE; -- 每次访问都先展示预览页
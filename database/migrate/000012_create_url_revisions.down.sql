@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS url_revisions;
+
+ALTER TABLE urls
+    DROP COLUMN IF EXISTS "title",
+    DROP COLUMN IF EXISTS "notes";
@@ -0,0 +1,21 @@
+ALTER TABLE urls
+    ADD COLUMN "title" TEXT NOT NULL DEFAULT '',
+    ADD COLUMN "notes" TEXT NOT NULL DEFAULT '';
+
+-- 每次修改目标地址、标题或备注后的内容
+CREATE TABLE IF NOT EXISTS url_revisions (
+    "id" BIGSERIAL PRIMARY KEY,
+    "url_id" BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    "original_url" TEXT NOT NULL,
+    "title" TEXT NOT NULL DEFAULT '',
+    "notes" TEXT NOT NULL DEFAULT '',
+    "user_id" INT NOT NULL,
+    "restored_from" BIGINT, -- 回滚产生的版本记录回滚到的版本
+    "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_url_revisions_url_id ON url_revisions(url_id, id);
+
+-- 已有的短链接以创建时的内容作为第一个版本
+INSERT INTO url_revisions (url_id, original_url, user_id, created_at)
+SELECT id, original_url, user_id, created_at FROM urls;
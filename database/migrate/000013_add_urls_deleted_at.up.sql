@@ -0,0 +1,4 @@
+ALTER TABLE urls
+    ADD COLUMN "deleted_at" TIMESTAMP; -- 移入回收站的时间，为空表示未删除
+
+CREATE INDEX idx_deleted_at ON urls(deleted_at) WHERE deleted_at IS NOT NULL; -- 清理回收站时使用
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_urls_domain_id_short_code;
+DROP INDEX IF EXISTS idx_urls_default_short_code;
+
+ALTER TABLE urls
+    DROP COLUMN IF EXISTS "domain_id";
+ALTER TABLE urls
+    ADD CONSTRAINT urls_short_code_key UNIQUE (short_code);
+
+DROP TABLE IF EXISTS domains;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS domains (
+    "id" BIGSERIAL PRIMARY KEY,
+    "user_id" INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    "host" CITEXT NOT NULL, -- 比较时忽略大小写
+    "verify_token" TEXT NOT NULL, -- 需要写入DNS TXT记录的验证值
+    "verified_at" TIMESTAMP, -- 为空表示尚未通过验证
+    "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_domains_user_id_host ON domains(user_id, host);
+CREATE UNIQUE INDEX idx_domains_verified_host ON domains(host) WHERE verified_at IS NOT NULL; -- 同一个域名只能被一个用户验证
+
+ALTER TABLE urls
+    ADD COLUMN "domain_id" BIGINT REFERENCES domains(id); -- 为空表示使用默认域名
+
+-- 短链接代码在各自的域名下唯一，管理接口按域名和代码定位短链接
+ALTER TABLE urls
+    DROP CONSTRAINT IF EXISTS urls_short_code_key;
+CREATE UNIQUE INDEX idx_urls_default_short_code ON urls(short_code) WHERE domain_id IS NULL;
+CREATE UNIQUE INDEX idx_urls_domain_id_short_code ON urls(domain_id, short_code) WHERE domain_id IS NOT NULL;
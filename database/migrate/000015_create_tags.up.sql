@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS tags (
+    "id" BIGSERIAL PRIMARY KEY,
+    "user_id" INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    "name" CITEXT NOT NULL, -- 比较时忽略大小写
+    "created_at" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_tags_user_id_name ON tags(user_id, name);
+
+CREATE TABLE IF NOT EXISTS url_tags (
+    "url_id" BIGINT NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    "tag_id" BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (url_id, tag_id)
+);
+
+CREATE INDEX idx_url_tags_tag_id ON url_tags(tag_id); -- 按标签筛选短链接
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_urls_notes_trgm;
+DROP INDEX IF EXISTS idx_urls_title_trgm;
+DROP INDEX IF EXISTS idx_urls_short_code_trgm;
+DROP INDEX IF EXISTS idx_urls_original_url_trgm;
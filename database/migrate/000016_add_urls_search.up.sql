@@ -0,0 +1,7 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- 三元组索引支持 ILIKE '%关键词%' 的模糊搜索
+CREATE INDEX idx_urls_original_url_trgm ON urls USING GIN (original_url gin_trgm_ops);
+CREATE INDEX idx_urls_short_code_trgm ON urls USING GIN (short_code gin_trgm_ops);
+CREATE INDEX idx_urls_title_trgm ON urls USING GIN (title gin_trgm_ops);
+CREATE INDEX idx_urls_notes_trgm ON urls USING GIN (notes gin_trgm_ops);
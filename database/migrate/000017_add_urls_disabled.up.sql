@@ -0,0 +1,2 @@
+ALTER TABLE urls
+    ADD COLUMN "disabled" BOOLEAN NOT NULL DEFAULT FALSE; -- 停用的短链接不再跳转，可以重新启用
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_urls_user_id_url_hash;
+
+ALTER TABLE urls
+    DROP COLUMN IF EXISTS "url_hash";
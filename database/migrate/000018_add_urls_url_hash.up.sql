@@ -0,0 +1,6 @@
+ALTER TABLE urls
+    ADD COLUMN "url_hash" TEXT NOT NULL DEFAULT ''; -- 规范化后的原始URL的SHA-256，用于查找重复的目标地址
+
+-- 已有数据的哈希需要与新写入的一样按规范化后的URL计算，SQL中无法规范化，由应用启动后回填
+
+CREATE INDEX idx_urls_user_id_url_hash ON urls (user_id, url_hash) WHERE NOT is_custom AND deleted_at IS NULL;
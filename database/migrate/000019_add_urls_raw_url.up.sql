@@ -0,0 +1,4 @@
+ALTER TABLE urls
+    ADD COLUMN "raw_url" TEXT NOT NULL DEFAULT ''; -- 用户输入的原始URL，original_url保存规范化后的形式
+
+UPDATE urls SET raw_url = original_url;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_urls_user_id_views_id;
+DROP INDEX IF EXISTS idx_urls_user_id_expired_at_id;
+DROP INDEX IF EXISTS idx_urls_user_id_created_at_id;
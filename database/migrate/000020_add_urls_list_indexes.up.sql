@@ -0,0 +1,4 @@
+-- 短链接列表按游标分页，按创建时间、过期时间和浏览量排序时使用，ID保证顺序唯一
+CREATE INDEX idx_urls_user_id_created_at_id ON urls (user_id, created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_urls_user_id_expired_at_id ON urls (user_id, expired_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_urls_user_id_views_id ON urls (user_id, views, id) WHERE deleted_at IS NULL;
@@ -0,0 +1,78 @@
+-- name: CreateClick :exec
+INSERT INTO clicks (
+    url_id,
+    destination,
+    referrer,
+    user_agent,
+    ip,
+    country,
+    city,
+    device,
+    os,
+    browser,
+    is_bot,
+    clicked_at,
+    variant_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+);
+
+-- name: CountClicksByInterval :many
+SELECT date_trunc(sqlc.arg(unit)::text, clicked_at AT TIME ZONE sqlc.arg(timezone)::text)::timestamp AS bucket,
+    COUNT(*) FILTER (WHERE NOT is_bot) AS clicks,
+    COUNT(*) FILTER (WHERE is_bot) AS bot_clicks
+FROM clicks
+WHERE url_id = sqlc.arg(url_id)
+AND clicked_at >= sqlc.arg(start_at)
+AND clicked_at < sqlc.arg(end_at)
+GROUP BY bucket
+ORDER BY bucket;
+
+-- name: CountClicksByCountry :many
+SELECT country, COUNT(*) AS clicks
+FROM clicks
+WHERE url_id = sqlc.arg(url_id)
+AND clicked_at >= sqlc.arg(start_at)
+AND clicked_at < sqlc.arg(end_at)
+AND NOT is_bot
+GROUP BY country
+ORDER BY clicks DESC;
+
+-- name: CountClicksByCity :many
+SELECT country, city, COUNT(*) AS clicks
+FROM clicks
+WHERE url_id = sqlc.arg(url_id)
+AND clicked_at >= sqlc.arg(start_at)
+AND clicked_at < sqlc.arg(end_at)
+AND NOT is_bot
+GROUP BY country, city
+ORDER BY clicks DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: CountClicksByUserAgent :many
+SELECT device, os, browser, is_bot, COUNT(*) AS clicks
+FROM clicks
+WHERE url_id = sqlc.arg(url_id)
+AND clicked_at >= sqlc.arg(start_at)
+AND clicked_at < sqlc.arg(end_at)
+GROUP BY device, os, browser, is_bot;
+
+-- name: CountUserClicksByUserAgent :many
+SELECT c.device, c.os, c.browser, c.is_bot, COUNT(*) AS clicks
+FROM clicks c
+JOIN urls u ON u.id = c.url_id
+WHERE u.user_id = sqlc.arg(user_id)
+AND c.clicked_at >= sqlc.arg(start_at)
+AND c.clicked_at < sqlc.arg(end_at)
+GROUP BY c.device, c.os, c.browser, c.is_bot;
+
+-- name: CountClicksByVariant :many
+SELECT variant_id::bigint AS variant_id,
+    COUNT(*) FILTER (WHERE NOT is_bot) AS clicks,
+    COUNT(*) FILTER (WHERE is_bot) AS bot_clicks
+FROM clicks
+WHERE url_id = sqlc.arg(url_id)
+AND clicked_at >= sqlc.arg(start_at)
+AND clicked_at < sqlc.arg(end_at)
+AND variant_id IS NOT NULL
+GROUP BY variant_id;
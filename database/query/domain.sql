@@ -0,0 +1,48 @@
+-- name: CreateDomain :one
+INSERT INTO domains (
+    user_id,
+    host,
+    verify_token
+) VALUES (
+    $1, $2, $3
+) RETURNING id, user_id, host, verify_token, verified_at, created_at;
+
+-- name: ListDomainsByUserID :many
+SELECT id, user_id, host, verify_token, verified_at, created_at
+FROM domains
+WHERE user_id = $1
+ORDER BY id;
+
+-- name: GetDomainByID :one
+SELECT id, user_id, host, verify_token, verified_at, created_at
+FROM domains
+WHERE id = $1
+AND user_id = $2;
+
+-- name: GetDomainByHost :one
+SELECT id, user_id, host, verify_token, verified_at, created_at
+FROM domains
+WHERE host = $1
+AND user_id = $2;
+
+-- name: IsDomainHostVerified :one
+SELECT EXISTS(
+    SELECT 1 FROM domains
+    WHERE host = $1
+    AND verified_at IS NOT NULL
+) AS is_verified;
+
+-- name: VerifyDomain :one
+UPDATE domains
+SET verified_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING id, user_id, host, verify_token, verified_at, created_at;
+
+-- name: CountURLsByDomainID :one
+SELECT COUNT(*) FROM urls
+WHERE domain_id = $1;
+
+-- name: DeleteDomain :execrows
+DELETE FROM domains
+WHERE id = $1
+AND user_id = $2;
@@ -0,0 +1,29 @@
+-- name: CreateURLRevision :one
+INSERT INTO url_revisions (
+    url_id,
+    original_url,
+    title,
+    notes,
+    user_id,
+    restored_from
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING id, url_id, original_url, title, notes, user_id, restored_from, created_at;
+
+-- name: ListURLRevisions :many
+SELECT id, url_id, original_url, title, notes, user_id, restored_from, created_at
+FROM url_revisions
+WHERE url_id = $1
+ORDER BY id DESC;
+
+-- name: GetURLRevision :one
+SELECT id, url_id, original_url, title, notes, user_id, restored_from, created_at
+FROM url_revisions
+WHERE id = $1
+AND url_id = $2;
+
+-- name: CreateInitialURLRevisions :exec
+INSERT INTO url_revisions (url_id, original_url, title, notes, user_id)
+SELECT id, original_url, title, notes, user_id
+FROM urls
+WHERE id = ANY(sqlc.arg(url_ids)::bigint[]);
@@ -0,0 +1,33 @@
+-- name: ListRulesByURLID :many
+SELECT id, url_id, position, condition_type, condition_value, timezone, destination, created_at
+FROM url_rules
+WHERE url_id = $1
+ORDER BY position, id;
+
+-- name: CreateRule :one
+INSERT INTO url_rules (
+    url_id,
+    position,
+    condition_type,
+    condition_value,
+    timezone,
+    destination
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING id, url_id, position, condition_type, condition_value, timezone, destination, created_at;
+
+-- name: UpdateRule :one
+UPDATE url_rules
+SET position = $1,
+    condition_type = $2,
+    condition_value = $3,
+    timezone = $4,
+    destination = $5
+WHERE id = $6
+AND url_id = $7
+RETURNING id, url_id, position, condition_type, condition_value, timezone, destination, created_at;
+
+-- name: DeleteRule :execrows
+DELETE FROM url_rules
+WHERE id = $1
+AND url_id = $2;
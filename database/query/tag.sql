@@ -0,0 +1,80 @@
+-- name: ListTagsByUserID :many
+SELECT id, user_id, name, created_at
+FROM tags
+WHERE user_id = $1
+ORDER BY name;
+
+-- name: GetTagIDByName :one
+SELECT id
+FROM tags
+WHERE name = $1
+AND user_id = $2;
+
+-- name: CreateTag :one
+INSERT INTO tags (
+    user_id,
+    name
+) VALUES (
+    $1, $2
+) RETURNING id, user_id, name, created_at;
+
+-- name: RenameTag :one
+UPDATE tags
+SET name = $1
+WHERE id = $2
+AND user_id = $3
+RETURNING id, user_id, name, created_at;
+
+-- name: DeleteTag :execrows
+DELETE FROM tags
+WHERE id = $1
+AND user_id = $2;
+
+-- name: IsTagOwnedByUser :one
+SELECT EXISTS(
+    SELECT 1 FROM tags
+    WHERE id = $1
+    AND user_id = $2
+) AS is_owned;
+
+-- name: AttachTag :exec
+INSERT INTO url_tags (
+    url_id,
+    tag_id
+) VALUES (
+    $1, $2
+) ON CONFLICT DO NOTHING;
+
+-- name: DetachTag :execrows
+DELETE FROM url_tags
+WHERE url_id = $1
+AND tag_id = $2;
+
+-- name: ListTagsByURLIDs :many
+SELECT ut.url_id, t.id, t.name
+FROM url_tags ut
+JOIN tags t ON t.id = ut.tag_id
+WHERE ut.url_id = ANY(sqlc.arg(url_ids)::bigint[])
+ORDER BY t.name;
+
+-- name: CreateTagsIfNotExist :exec
+INSERT INTO tags (user_id, name)
+SELECT sqlc.arg(user_id)::int, unnest(sqlc.arg(names)::citext[])
+ON CONFLICT DO NOTHING;
+
+-- name: ListTagsByNames :many
+SELECT id, user_id, name, created_at
+FROM tags
+WHERE user_id = sqlc.arg(user_id)
+AND name = ANY(sqlc.arg(names)::citext[]);
+
+-- name: AttachTags :exec
+INSERT INTO url_tags (url_id, tag_id)
+SELECT u.url_id, u.tag_id
+FROM unnest(sqlc.arg(url_ids)::bigint[], sqlc.arg(tag_ids)::bigint[]) AS u(url_id, tag_id)
+ON CONFLICT DO NOTHING;
+
+-- name: DetachTagFromURLs :exec
+DELETE FROM url_tags
+WHERE tag_id = sqlc.arg(tag_id)
+AND url_id = ANY(sqlc.arg(url_ids)::bigint[]);
@@ -1,43 +1,620 @@
--- name: CreateURL :exec
+-- name: CreateURL :one
 INSERT INTO urls (
     original_url,
     short_code,
     is_custom,
     expired_at,
-    user_id
+    user_id,
+    password_hash,
+    max_clicks,
+    active_from,
+    redirect_type,
+    always_preview,
+    title,
+    notes,
+    domain_id,
+    url_hash,
+    raw_url
 ) VALUES (
-    $1, $2, $3, $4, $5
-);
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+) RETURNING id;
+
+-- name: BulkCreateURLs :many
+INSERT INTO urls (
+    original_url,
+    short_code,
+    is_custom,
+    expired_at,
+    user_id,
+    title,
+    notes,
+    domain_id,
+    url_hash,
+    raw_url
+)
+SELECT u.original_url, u.short_code, u.is_custom, CURRENT_TIMESTAMP + u.ttl * INTERVAL '1 second', sqlc.arg(user_id)::int, u.title, u.notes, sqlc.narg(domain_id)::bigint, u.url_hash, u.raw_url
+FROM unnest(
+    sqlc.arg(original_urls)::text[],
+    sqlc.arg(short_codes)::text[],
+    sqlc.arg(is_customs)::boolean[],
+    sqlc.arg(ttls)::bigint[],
+    sqlc.arg(titles)::text[],
+    sqlc.arg(notes)::text[],
+    sqlc.arg(url_hashes)::text[],
+    sqlc.arg(raw_urls)::text[]
+) AS u(original_url, short_code, is_custom, ttl, title, notes, url_hash, raw_url)
+ON CONFLICT DO NOTHING
+RETURNING id, short_code;
 
 -- name: IsShortCodeAvailable :one
 SELECT NOT EXISTS(
     SELECT 1 FROM urls
-    WHERE short_code = $1
+    WHERE short_code = sqlc.arg(short_code)
+    AND domain_id IS NOT DISTINCT FROM sqlc.narg(domain_id)
 ) AS is_available;
 
--- name: GetUrlByShortCode :one
-SELECT original_url, short_code, views, is_custom FROM urls 
-WHERE short_code = $1
-AND expired_at > CURRENT_TIMESTAMP;
+-- name: GetURLByHostAndShortCode :one
+SELECT u.id, u.original_url, u.short_code, u.views, u.is_custom, u.password_hash, u.max_clicks, u.active_from, u.redirect_type, u.always_preview, u.expired_at, u.created_at, COALESCE(d.host, '')::text AS host
+FROM urls u
+LEFT JOIN domains d ON d.id = u.domain_id
+WHERE u.short_code = sqlc.arg(short_code)
+AND u.domain_id IS NOT DISTINCT FROM (
+    SELECT id FROM domains
+    WHERE domains.host = sqlc.arg(host)
+    AND verified_at IS NOT NULL
+)
+AND u.deleted_at IS NULL
+AND NOT u.disabled
+AND u.expired_at > CURRENT_TIMESTAMP
+AND (u.max_clicks IS NULL OR u.redirect_count < u.max_clicks);
+
+-- name: ConsumeURLClick :one
+UPDATE urls
+SET redirect_count = redirect_count + 1
+WHERE id = $1
+AND deleted_at IS NULL
+AND NOT disabled
+AND expired_at > CURRENT_TIMESTAMP
+AND redirect_count < max_clicks
+RETURNING (max_clicks - redirect_count)::int AS remaining;
 
 
--- name: UpdateViewsByShortCode :exec
+-- name: GetViewsByID :one
+SELECT views FROM urls
+WHERE id = $1;
+
+-- name: UpdateViewsByHostAndShortCode :exec
 UPDATE urls
-SET views = views + $1
-WHERE short_code = $2;
+SET views = views + sqlc.arg(views)
+WHERE short_code = sqlc.arg(short_code)
+AND domain_id IS NOT DISTINCT FROM (
+    SELECT id FROM domains
+    WHERE domains.host = sqlc.arg(host)
+    AND verified_at IS NOT NULL
+);
+
+-- name: GetURLsByUserIDCreatedAtAsc :many
+-- 每种排序方式和方向各用一个查询，游标条件和ORDER BY都是固定的列，才能使用索引
+SELECT r.id, r.original_url, r.raw_url, r.short_code, r.views, r.is_custom, r.expired_at, r.max_clicks, r.redirect_count, r.title, r.notes, r.disabled, r.created_at, COALESCE(d.host, '')::text AS host
+FROM urls r
+LEFT JOIN domains d ON d.id = r.domain_id
+WHERE r.user_id = sqlc.arg(user_id)
+AND r.deleted_at IS NULL
+AND (cardinality(sqlc.arg(tags)::citext[]) = 0 OR r.id IN (
+    SELECT ut.url_id
+    FROM url_tags ut
+    JOIN tags t ON t.id = ut.tag_id
+    WHERE t.user_id = sqlc.arg(user_id)
+    AND t.name = ANY(sqlc.arg(tags)::citext[])
+    GROUP BY ut.url_id
+    HAVING COUNT(*) = cardinality(sqlc.arg(tags)::citext[])
+))
+AND (sqlc.narg(pattern)::text IS NULL
+    OR r.original_url ILIKE sqlc.narg(pattern)::text
+    OR r.short_code ILIKE sqlc.narg(pattern)::text
+    OR r.title ILIKE sqlc.narg(pattern)::text
+    OR r.notes ILIKE sqlc.narg(pattern)::text)
+AND (sqlc.narg(status)::text IS NULL
+    OR (sqlc.narg(status)::text = 'active'
+        AND NOT r.disabled
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND (r.active_from IS NULL OR r.active_from <= CURRENT_TIMESTAMP)
+        AND (r.max_clicks IS NULL OR r.redirect_count < r.max_clicks))
+    OR (sqlc.narg(status)::text = 'expired'
+        AND (r.expired_at <= CURRENT_TIMESTAMP OR r.redirect_count >= COALESCE(r.max_clicks, r.redirect_count + 1)))
+    OR (sqlc.narg(status)::text = 'disabled'
+        AND r.disabled)
+    OR (sqlc.narg(status)::text = 'scheduled'
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND r.active_from > CURRENT_TIMESTAMP))
+AND (sqlc.narg(is_custom)::boolean IS NULL OR r.is_custom = sqlc.narg(is_custom)::boolean)
+AND (sqlc.narg(created_from)::timestamp IS NULL OR r.created_at >= sqlc.narg(created_from)::timestamp)
+AND (sqlc.narg(created_to)::timestamp IS NULL OR r.created_at < sqlc.narg(created_to)::timestamp)
+AND (sqlc.narg(min_views)::int IS NULL OR r.views >= sqlc.narg(min_views)::int)
+AND (sqlc.narg(max_views)::int IS NULL OR r.views <= sqlc.narg(max_views)::int)
+AND (sqlc.narg(cursor_id)::bigint IS NULL OR (r.created_at, r.id) > (sqlc.narg(cursor_time)::timestamp, sqlc.narg(cursor_id)::bigint))
+ORDER BY r.created_at, r.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetURLsByUserIDCreatedAtDesc :many
+SELECT r.id, r.original_url, r.raw_url, r.short_code, r.views, r.is_custom, r.expired_at, r.max_clicks, r.redirect_count, r.title, r.notes, r.disabled, r.created_at, COALESCE(d.host, '')::text AS host
+FROM urls r
+LEFT JOIN domains d ON d.id = r.domain_id
+WHERE r.user_id = sqlc.arg(user_id)
+AND r.deleted_at IS NULL
+AND (cardinality(sqlc.arg(tags)::citext[]) = 0 OR r.id IN (
+    SELECT ut.url_id
+    FROM url_tags ut
+    JOIN tags t ON t.id = ut.tag_id
+    WHERE t.user_id = sqlc.arg(user_id)
+    AND t.name = ANY(sqlc.arg(tags)::citext[])
+    GROUP BY ut.url_id
+    HAVING COUNT(*) = cardinality(sqlc.arg(tags)::citext[])
+))
+AND (sqlc.narg(pattern)::text IS NULL
+    OR r.original_url ILIKE sqlc.narg(pattern)::text
+    OR r.short_code ILIKE sqlc.narg(pattern)::text
+    OR r.title ILIKE sqlc.narg(pattern)::text
+    OR r.notes ILIKE sqlc.narg(pattern)::text)
+AND (sqlc.narg(status)::text IS NULL
+    OR (sqlc.narg(status)::text = 'active'
+        AND NOT r.disabled
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND (r.active_from IS NULL OR r.active_from <= CURRENT_TIMESTAMP)
+        AND (r.max_clicks IS NULL OR r.redirect_count < r.max_clicks))
+    OR (sqlc.narg(status)::text = 'expired'
+        AND (r.expired_at <= CURRENT_TIMESTAMP OR r.redirect_count >= COALESCE(r.max_clicks, r.redirect_count + 1)))
+    OR (sqlc.narg(status)::text = 'disabled'
+        AND r.disabled)
+    OR (sqlc.narg(status)::text = 'scheduled'
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND r.active_from > CURRENT_TIMESTAMP))
+AND (sqlc.narg(is_custom)::boolean IS NULL OR r.is_custom = sqlc.narg(is_custom)::boolean)
+AND (sqlc.narg(created_from)::timestamp IS NULL OR r.created_at >= sqlc.narg(created_from)::timestamp)
+AND (sqlc.narg(created_to)::timestamp IS NULL OR r.created_at < sqlc.narg(created_to)::timestamp)
+AND (sqlc.narg(min_views)::int IS NULL OR r.views >= sqlc.narg(min_views)::int)
+AND (sqlc.narg(max_views)::int IS NULL OR r.views <= sqlc.narg(max_views)::int)
+AND (sqlc.narg(cursor_id)::bigint IS NULL OR (r.created_at, r.id) < (sqlc.narg(cursor_time)::timestamp, sqlc.narg(cursor_id)::bigint))
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetURLsByUserIDExpiredAtAsc :many
+SELECT r.id, r.original_url, r.raw_url, r.short_code, r.views, r.is_custom, r.expired_at, r.max_clicks, r.redirect_count, r.title, r.notes, r.disabled, r.created_at, COALESCE(d.host, '')::text AS host
+FROM urls r
+LEFT JOIN domains d ON d.id = r.domain_id
+WHERE r.user_id = sqlc.arg(user_id)
+AND r.deleted_at IS NULL
+AND (cardinality(sqlc.arg(tags)::citext[]) = 0 OR r.id IN (
+    SELECT ut.url_id
+    FROM url_tags ut
+    JOIN tags t ON t.id = ut.tag_id
+    WHERE t.user_id = sqlc.arg(user_id)
+    AND t.name = ANY(sqlc.arg(tags)::citext[])
+    GROUP BY ut.url_id
+    HAVING COUNT(*) = cardinality(sqlc.arg(tags)::citext[])
+))
+AND (sqlc.narg(pattern)::text IS NULL
+    OR r.original_url ILIKE sqlc.narg(pattern)::text
+    OR r.short_code ILIKE sqlc.narg(pattern)::text
+    OR r.title ILIKE sqlc.narg(pattern)::text
+    OR r.notes ILIKE sqlc.narg(pattern)::text)
+AND (sqlc.narg(status)::text IS NULL
+    OR (sqlc.narg(status)::text = 'active'
+        AND NOT r.disabled
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND (r.active_from IS NULL OR r.active_from <= CURRENT_TIMESTAMP)
+        AND (r.max_clicks IS NULL OR r.redirect_count < r.max_clicks))
+    OR (sqlc.narg(status)::text = 'expired'
+        AND (r.expired_at <= CURRENT_TIMESTAMP OR r.redirect_count >= COALESCE(r.max_clicks, r.redirect_count + 1)))
+    OR (sqlc.narg(status)::text = 'disabled'
+        AND r.disabled)
+    OR (sqlc.narg(status)::text = 'scheduled'
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND r.active_from > CURRENT_TIMESTAMP))
+AND (sqlc.narg(is_custom)::boolean IS NULL OR r.is_custom = sqlc.narg(is_custom)::boolean)
+AND (sqlc.narg(created_from)::timestamp IS NULL OR r.created_at >= sqlc.narg(created_from)::timestamp)
+AND (sqlc.narg(created_to)::timestamp IS NULL OR r.created_at < sqlc.narg(created_to)::timestamp)
+AND (sqlc.narg(min_views)::int IS NULL OR r.views >= sqlc.narg(min_views)::int)
+AND (sqlc.narg(max_views)::int IS NULL OR r.views <= sqlc.narg(max_views)::int)
+AND (sqlc.narg(cursor_id)::bigint IS NULL OR (r.expired_at, r.id) > (sqlc.narg(cursor_time)::timestamp, sqlc.narg(cursor_id)::bigint))
+ORDER BY r.expired_at, r.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetURLsByUserIDExpiredAtDesc :many
+SELECT r.id, r.original_url, r.raw_url, r.short_code, r.views, r.is_custom, r.expired_at, r.max_clicks, r.redirect_count, r.title, r.notes, r.disabled, r.created_at, COALESCE(d.host, '')::text AS host
+FROM urls r
+LEFT JOIN domains d ON d.id = r.domain_id
+WHERE r.user_id = sqlc.arg(user_id)
+AND r.deleted_at IS NULL
+AND (cardinality(sqlc.arg(tags)::citext[]) = 0 OR r.id IN (
+    SELECT ut.url_id
+    FROM url_tags ut
+    JOIN tags t ON t.id = ut.tag_id
+    WHERE t.user_id = sqlc.arg(user_id)
+    AND t.name = ANY(sqlc.arg(tags)::citext[])
+    GROUP BY ut.url_id
+    HAVING COUNT(*) = cardinality(sqlc.arg(tags)::citext[])
+))
+AND (sqlc.narg(pattern)::text IS NULL
+    OR r.original_url ILIKE sqlc.narg(pattern)::text
+    OR r.short_code ILIKE sqlc.narg(pattern)::text
+    OR r.title ILIKE sqlc.narg(pattern)::text
+    OR r.notes ILIKE sqlc.narg(pattern)::text)
+AND (sqlc.narg(status)::text IS NULL
+    OR (sqlc.narg(status)::text = 'active'
+        AND NOT r.disabled
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND (r.active_from IS NULL OR r.active_from <= CURRENT_TIMESTAMP)
+        AND (r.max_clicks IS NULL OR r.redirect_count < r.max_clicks))
+    OR (sqlc.narg(status)::text = 'expired'
+        AND (r.expired_at <= CURRENT_TIMESTAMP OR r.redirect_count >= COALESCE(r.max_clicks, r.redirect_count + 1)))
+    OR (sqlc.narg(status)::text = 'disabled'
+        AND r.disabled)
+    OR (sqlc.narg(status)::text = 'scheduled'
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND r.active_from > CURRENT_TIMESTAMP))
+AND (sqlc.narg(is_custom)::boolean IS NULL OR r.is_custom = sqlc.narg(is_custom)::boolean)
+AND (sqlc.narg(created_from)::timestamp IS NULL OR r.created_at >= sqlc.narg(created_from)::timestamp)
+AND (sqlc.narg(created_to)::timestamp IS NULL OR r.created_at < sqlc.narg(created_to)::timestamp)
+AND (sqlc.narg(min_views)::int IS NULL OR r.views >= sqlc.narg(min_views)::int)
+AND (sqlc.narg(max_views)::int IS NULL OR r.views <= sqlc.narg(max_views)::int)
+AND (sqlc.narg(cursor_id)::bigint IS NULL OR (r.expired_at, r.id) < (sqlc.narg(cursor_time)::timestamp, sqlc.narg(cursor_id)::bigint))
+ORDER BY r.expired_at DESC, r.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetURLsByUserIDOriginalURLAsc :many
+SELECT r.id, r.original_url, r.raw_url, r.short_code, r.views, r.is_custom, r.expired_at, r.max_clicks, r.redirect_count, r.title, r.notes, r.disabled, r.created_at, COALESCE(d.host, '')::text AS host
+FROM urls r
+LEFT JOIN domains d ON d.id = r.domain_id
+WHERE r.user_id = sqlc.arg(user_id)
+AND r.deleted_at IS NULL
+AND (cardinality(sqlc.arg(tags)::citext[]) = 0 OR r.id IN (
+    SELECT ut.url_id
+    FROM url_tags ut
+    JOIN tags t ON t.id = ut.tag_id
+    WHERE t.user_id = sqlc.arg(user_id)
+    AND t.name = ANY(sqlc.arg(tags)::citext[])
+    GROUP BY ut.url_id
+    HAVING COUNT(*) = cardinality(sqlc.arg(tags)::citext[])
+))
+AND (sqlc.narg(pattern)::text IS NULL
+    OR r.original_url ILIKE sqlc.narg(pattern)::text
+    OR r.short_code ILIKE sqlc.narg(pattern)::text
+    OR r.title ILIKE sqlc.narg(pattern)::text
+    OR r.notes ILIKE sqlc.narg(pattern)::text)
+AND (sqlc.narg(status)::text IS NULL
+    OR (sqlc.narg(status)::text = 'active'
+        AND NOT r.disabled
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND (r.active_from IS NULL OR r.active_from <= CURRENT_TIMESTAMP)
+        AND (r.max_clicks IS NULL OR r.redirect_count < r.max_clicks))
+    OR (sqlc.narg(status)::text = 'expired'
+        AND (r.expired_at <= CURRENT_TIMESTAMP OR r.redirect_count >= COALESCE(r.max_clicks, r.redirect_count + 1)))
+    OR (sqlc.narg(status)::text = 'disabled'
+        AND r.disabled)
+    OR (sqlc.narg(status)::text = 'scheduled'
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND r.active_from > CURRENT_TIMESTAMP))
+AND (sqlc.narg(is_custom)::boolean IS NULL OR r.is_custom = sqlc.narg(is_custom)::boolean)
+AND (sqlc.narg(created_from)::timestamp IS NULL OR r.created_at >= sqlc.narg(created_from)::timestamp)
+AND (sqlc.narg(created_to)::timestamp IS NULL OR r.created_at < sqlc.narg(created_to)::timestamp)
+AND (sqlc.narg(min_views)::int IS NULL OR r.views >= sqlc.narg(min_views)::int)
+AND (sqlc.narg(max_views)::int IS NULL OR r.views <= sqlc.narg(max_views)::int)
+AND (sqlc.narg(cursor_id)::bigint IS NULL OR (r.original_url, r.id) > (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::bigint))
+ORDER BY r.original_url, r.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
--- name: GetURLsByUserID :many
-SELECT id, original_url, short_code, views, is_custom, expired_at, COUNT(*) OVER() AS total
+-- name: GetURLsByUserIDOriginalURLDesc :many
+SELECT r.id, r.original_url, r.raw_url, r.short_code, r.views, r.is_custom, r.expired_at, r.max_clicks, r.redirect_count, r.title, r.notes, r.disabled, r.created_at, COALESCE(d.host, '')::text AS host
 FROM urls r
+LEFT JOIN domains d ON d.id = r.domain_id
+WHERE r.user_id = sqlc.arg(user_id)
+AND r.deleted_at IS NULL
+AND (cardinality(sqlc.arg(tags)::citext[]) = 0 OR r.id IN (
+    SELECT ut.url_id
+    FROM url_tags ut
+    JOIN tags t ON t.id = ut.tag_id
+    WHERE t.user_id = sqlc.arg(user_id)
+    AND t.name = ANY(sqlc.arg(tags)::citext[])
+    GROUP BY ut.url_id
+    HAVING COUNT(*) = cardinality(sqlc.arg(tags)::citext[])
+))
+AND (sqlc.narg(pattern)::text IS NULL
+    OR r.original_url ILIKE sqlc.narg(pattern)::text
+    OR r.short_code ILIKE sqlc.narg(pattern)::text
+    OR r.title ILIKE sqlc.narg(pattern)::text
+    OR r.notes ILIKE sqlc.narg(pattern)::text)
+AND (sqlc.narg(status)::text IS NULL
+    OR (sqlc.narg(status)::text = 'active'
+        AND NOT r.disabled
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND (r.active_from IS NULL OR r.active_from <= CURRENT_TIMESTAMP)
+        AND (r.max_clicks IS NULL OR r.redirect_count < r.max_clicks))
+    OR (sqlc.narg(status)::text = 'expired'
+        AND (r.expired_at <= CURRENT_TIMESTAMP OR r.redirect_count >= COALESCE(r.max_clicks, r.redirect_count + 1)))
+    OR (sqlc.narg(status)::text = 'disabled'
+        AND r.disabled)
+    OR (sqlc.narg(status)::text = 'scheduled'
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND r.active_from > CURRENT_TIMESTAMP))
+AND (sqlc.narg(is_custom)::boolean IS NULL OR r.is_custom = sqlc.narg(is_custom)::boolean)
+AND (sqlc.narg(created_from)::timestamp IS NULL OR r.created_at >= sqlc.narg(created_from)::timestamp)
+AND (sqlc.narg(created_to)::timestamp IS NULL OR r.created_at < sqlc.narg(created_to)::timestamp)
+AND (sqlc.narg(min_views)::int IS NULL OR r.views >= sqlc.narg(min_views)::int)
+AND (sqlc.narg(max_views)::int IS NULL OR r.views <= sqlc.narg(max_views)::int)
+AND (sqlc.narg(cursor_id)::bigint IS NULL OR (r.original_url, r.id) < (sqlc.narg(cursor_text)::text, sqlc.narg(cursor_id)::bigint))
+ORDER BY r.original_url DESC, r.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetURLsByUserIDViewsAsc :many
+SELECT r.id, r.original_url, r.raw_url, r.short_code, r.views, r.is_custom, r.expired_at, r.max_clicks, r.redirect_count, r.title, r.notes, r.disabled, r.created_at, COALESCE(d.host, '')::text AS host
+FROM urls r
+LEFT JOIN domains d ON d.id = r.domain_id
+WHERE r.user_id = sqlc.arg(user_id)
+AND r.deleted_at IS NULL
+AND (cardinality(sqlc.arg(tags)::citext[]) = 0 OR r.id IN (
+    SELECT ut.url_id
+    FROM url_tags ut
+    JOIN tags t ON t.id = ut.tag_id
+    WHERE t.user_id = sqlc.arg(user_id)
+    AND t.name = ANY(sqlc.arg(tags)::citext[])
+    GROUP BY ut.url_id
+    HAVING COUNT(*) = cardinality(sqlc.arg(tags)::citext[])
+))
+AND (sqlc.narg(pattern)::text IS NULL
+    OR r.original_url ILIKE sqlc.narg(pattern)::text
+    OR r.short_code ILIKE sqlc.narg(pattern)::text
+    OR r.title ILIKE sqlc.narg(pattern)::text
+    OR r.notes ILIKE sqlc.narg(pattern)::text)
+AND (sqlc.narg(status)::text IS NULL
+    OR (sqlc.narg(status)::text = 'active'
+        AND NOT r.disabled
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND (r.active_from IS NULL OR r.active_from <= CURRENT_TIMESTAMP)
+        AND (r.max_clicks IS NULL OR r.redirect_count < r.max_clicks))
+    OR (sqlc.narg(status)::text = 'expired'
+        AND (r.expired_at <= CURRENT_TIMESTAMP OR r.redirect_count >= COALESCE(r.max_clicks, r.redirect_count + 1)))
+    OR (sqlc.narg(status)::text = 'disabled'
+        AND r.disabled)
+    OR (sqlc.narg(status)::text = 'scheduled'
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND r.active_from > CURRENT_TIMESTAMP))
+AND (sqlc.narg(is_custom)::boolean IS NULL OR r.is_custom = sqlc.narg(is_custom)::boolean)
+AND (sqlc.narg(created_from)::timestamp IS NULL OR r.created_at >= sqlc.narg(created_from)::timestamp)
+AND (sqlc.narg(created_to)::timestamp IS NULL OR r.created_at < sqlc.narg(created_to)::timestamp)
+AND (sqlc.narg(min_views)::int IS NULL OR r.views >= sqlc.narg(min_views)::int)
+AND (sqlc.narg(max_views)::int IS NULL OR r.views <= sqlc.narg(max_views)::int)
+AND (sqlc.narg(cursor_id)::bigint IS NULL OR (r.views, r.id) > (sqlc.narg(cursor_views)::int, sqlc.narg(cursor_id)::bigint))
+ORDER BY r.views, r.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetURLsByUserIDViewsDesc :many
+SELECT r.id, r.original_url, r.raw_url, r.short_code, r.views, r.is_custom, r.expired_at, r.max_clicks, r.redirect_count, r.title, r.notes, r.disabled, r.created_at, COALESCE(d.host, '')::text AS host
+FROM urls r
+LEFT JOIN domains d ON d.id = r.domain_id
+WHERE r.user_id = sqlc.arg(user_id)
+AND r.deleted_at IS NULL
+AND (cardinality(sqlc.arg(tags)::citext[]) = 0 OR r.id IN (
+    SELECT ut.url_id
+    FROM url_tags ut
+    JOIN tags t ON t.id = ut.tag_id
+    WHERE t.user_id = sqlc.arg(user_id)
+    AND t.name = ANY(sqlc.arg(tags)::citext[])
+    GROUP BY ut.url_id
+    HAVING COUNT(*) = cardinality(sqlc.arg(tags)::citext[])
+))
+AND (sqlc.narg(pattern)::text IS NULL
+    OR r.original_url ILIKE sqlc.narg(pattern)::text
+    OR r.short_code ILIKE sqlc.narg(pattern)::text
+    OR r.title ILIKE sqlc.narg(pattern)::text
+    OR r.notes ILIKE sqlc.narg(pattern)::text)
+AND (sqlc.narg(status)::text IS NULL
+    OR (sqlc.narg(status)::text = 'active'
+        AND NOT r.disabled
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND (r.active_from IS NULL OR r.active_from <= CURRENT_TIMESTAMP)
+        AND (r.max_clicks IS NULL OR r.redirect_count < r.max_clicks))
+    OR (sqlc.narg(status)::text = 'expired'
+        AND (r.expired_at <= CURRENT_TIMESTAMP OR r.redirect_count >= COALESCE(r.max_clicks, r.redirect_count + 1)))
+    OR (sqlc.narg(status)::text = 'disabled'
+        AND r.disabled)
+    OR (sqlc.narg(status)::text = 'scheduled'
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND r.active_from > CURRENT_TIMESTAMP))
+AND (sqlc.narg(is_custom)::boolean IS NULL OR r.is_custom = sqlc.narg(is_custom)::boolean)
+AND (sqlc.narg(created_from)::timestamp IS NULL OR r.created_at >= sqlc.narg(created_from)::timestamp)
+AND (sqlc.narg(created_to)::timestamp IS NULL OR r.created_at < sqlc.narg(created_to)::timestamp)
+AND (sqlc.narg(min_views)::int IS NULL OR r.views >= sqlc.narg(min_views)::int)
+AND (sqlc.narg(max_views)::int IS NULL OR r.views <= sqlc.narg(max_views)::int)
+AND (sqlc.narg(cursor_id)::bigint IS NULL OR (r.views, r.id) < (sqlc.narg(cursor_views)::int, sqlc.narg(cursor_id)::bigint))
+ORDER BY r.views DESC, r.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountURLsByUserID :one
+SELECT COUNT(*)
+FROM urls r
+WHERE r.user_id = sqlc.arg(user_id)
+AND r.deleted_at IS NULL
+AND (cardinality(sqlc.arg(tags)::citext[]) = 0 OR r.id IN (
+    SELECT ut.url_id
+    FROM url_tags ut
+    JOIN tags t ON t.id = ut.tag_id
+    WHERE t.user_id = sqlc.arg(user_id)
+    AND t.name = ANY(sqlc.arg(tags)::citext[])
+    GROUP BY ut.url_id
+    HAVING COUNT(*) = cardinality(sqlc.arg(tags)::citext[])
+))
+AND (sqlc.narg(pattern)::text IS NULL
+    OR r.original_url ILIKE sqlc.narg(pattern)::text
+    OR r.short_code ILIKE sqlc.narg(pattern)::text
+    OR r.title ILIKE sqlc.narg(pattern)::text
+    OR r.notes ILIKE sqlc.narg(pattern)::text)
+AND (sqlc.narg(status)::text IS NULL
+    OR (sqlc.narg(status)::text = 'active'
+        AND NOT r.disabled
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND (r.active_from IS NULL OR r.active_from <= CURRENT_TIMESTAMP)
+        AND (r.max_clicks IS NULL OR r.redirect_count < r.max_clicks))
+    OR (sqlc.narg(status)::text = 'expired'
+        AND (r.expired_at <= CURRENT_TIMESTAMP OR r.redirect_count >= COALESCE(r.max_clicks, r.redirect_count + 1)))
+    OR (sqlc.narg(status)::text = 'disabled'
+        AND r.disabled)
+    OR (sqlc.narg(status)::text = 'scheduled'
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND r.active_from > CURRENT_TIMESTAMP))
+AND (sqlc.narg(is_custom)::boolean IS NULL OR r.is_custom = sqlc.narg(is_custom)::boolean)
+AND (sqlc.narg(created_from)::timestamp IS NULL OR r.created_at >= sqlc.narg(created_from)::timestamp)
+AND (sqlc.narg(created_to)::timestamp IS NULL OR r.created_at < sqlc.narg(created_to)::timestamp)
+AND (sqlc.narg(min_views)::int IS NULL OR r.views >= sqlc.narg(min_views)::int)
+AND (sqlc.narg(max_views)::int IS NULL OR r.views <= sqlc.narg(max_views)::int);
+
+-- name: ExportURLsByUserID :many
+SELECT r.id, r.original_url, r.short_code, r.title, r.notes, r.is_custom, r.views, r.created_at, r.expired_at, COALESCE(d.host, '')::text AS host,
+    COALESCE(array_agg(t.name ORDER BY t.name) FILTER (WHERE t.id IS NOT NULL), '{}')::text[] AS tags
+FROM urls r
+LEFT JOIN domains d ON d.id = r.domain_id
+LEFT JOIN url_tags ut ON ut.url_id = r.id
+LEFT JOIN tags t ON t.id = ut.tag_id
+WHERE r.user_id = $1
+AND r.deleted_at IS NULL
+AND r.id > $2
+GROUP BY r.id, d.host
+ORDER BY r.id
+LIMIT $3;
+
+-- name: GetTrashedURLsByUserID :many
+SELECT r.id, r.original_url, r.short_code, r.title, r.deleted_at, COALESCE(d.host, '')::text AS host, COUNT(*) OVER() AS total
+FROM urls r
+LEFT JOIN domains d ON d.id = r.domain_id
 WHERE r.user_id = $1
-ORDER BY created_at DESC
+AND r.deleted_at IS NOT NULL
+ORDER BY r.deleted_at DESC
 LIMIT $2 OFFSET $3;
 
--- name: DeleteURLByShortCode :exec
+-- name: TrashURLByID :exec
+UPDATE urls
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1
+AND deleted_at IS NULL;
+
+-- name: RestoreURLByID :exec
+UPDATE urls
+SET deleted_at = NULL
+WHERE id = $1;
+
+-- name: DeleteURLByID :exec
+DELETE FROM urls
+WHERE id = $1;
+
+-- name: PurgeTrashedURLs :many
 DELETE FROM urls
-WHERE short_code = $1;
+WHERE id IN (
+    SELECT id FROM urls
+    WHERE deleted_at < sqlc.arg(deleted_before)
+    LIMIT sqlc.arg(row_limit)
+)
+RETURNING short_code, COALESCE((SELECT host FROM domains WHERE domains.id = urls.domain_id), '')::text AS host;
+
+-- name: UpdateURLSettingsByID :exec
+UPDATE urls
+SET expired_at = COALESCE(sqlc.narg(expired_at), expired_at),
+    active_from = CASE WHEN sqlc.arg(clear_active_from)::boolean THEN NULL ELSE COALESCE(sqlc.narg(active_from), active_from) END,
+    redirect_type = COALESCE(sqlc.narg(redirect_type), redirect_type),
+    always_preview = COALESCE(sqlc.narg(always_preview), always_preview)
+WHERE id = sqlc.arg(id);
+
+-- name: GetActiveURLByHash :one
+-- 查找用户已有的、可以正常跳转的同一目标地址的非自定义短链接
+SELECT u.short_code, COALESCE(d.host, '')::text AS host
+FROM urls u
+LEFT JOIN domains d ON d.id = u.domain_id
+WHERE u.user_id = $1
+AND u.url_hash = $2
+AND u.domain_id IS NOT DISTINCT FROM $3
+AND u.redirect_type = $4
+AND u.always_preview = $5
+AND NOT u.is_custom
+AND u.deleted_at IS NULL
+AND NOT u.disabled
+AND u.password_hash = ''
+AND u.max_clicks IS NULL
+AND u.active_from IS NULL
+AND u.expired_at > CURRENT_TIMESTAMP
+AND NOT EXISTS (SELECT 1 FROM url_rules r WHERE r.url_id = u.id)
+AND NOT EXISTS (SELECT 1 FROM url_variants v WHERE v.url_id = u.id)
+ORDER BY u.expired_at DESC
+LIMIT 1;
+
+-- name: ListURLsWithoutHash :many
+-- 迁移前创建的短链接还没有url_hash，由应用使用同一规范化规则回填
+SELECT id, original_url FROM urls
+WHERE url_hash = ''
+ORDER BY id
+LIMIT $1;
+
+-- name: UpdateURLHash :exec
+UPDATE urls SET url_hash = $2
+WHERE id = $1;
+
+-- name: GetURLByShortCodeAndUserID :one
+-- host为空表示默认域名
+SELECT u.id, COALESCE(d.host, '')::text AS host
+FROM urls u
+LEFT JOIN domains d ON d.id = u.domain_id
+WHERE u.short_code = sqlc.arg(short_code)
+AND u.user_id = sqlc.arg(user_id)
+AND COALESCE(d.host, '') = sqlc.arg(host)::citext
+AND u.deleted_at IS NULL;
+
+-- name: GetTrashedURLByShortCodeAndUserID :one
+SELECT u.id, COALESCE(d.host, '')::text AS host
+FROM urls u
+LEFT JOIN domains d ON d.id = u.domain_id
+WHERE u.short_code = sqlc.arg(short_code)
+AND u.user_id = sqlc.arg(user_id)
+AND COALESCE(d.host, '') = sqlc.arg(host)::citext
+AND u.deleted_at IS NOT NULL;
+
+-- name: GetURLContentForUpdate :one
+SELECT original_url, title, notes FROM urls
+WHERE id = $1
+FOR UPDATE;
+
+-- name: UpdateURLContentByID :exec
+UPDATE urls
+SET original_url = sqlc.arg(original_url),
+    raw_url = COALESCE(sqlc.narg(raw_url), raw_url),
+    title = sqlc.arg(title),
+    notes = sqlc.arg(notes),
+    url_hash = sqlc.arg(url_hash)
+WHERE id = sqlc.arg(id);
+
+-- name: GetURLsByShortCodesAndUserID :many
+SELECT u.id, u.short_code, COALESCE(d.host, '')::text AS host
+FROM urls u
+LEFT JOIN domains d ON d.id = u.domain_id
+WHERE u.short_code = ANY(sqlc.arg(short_codes)::text[])
+AND u.user_id = sqlc.arg(user_id)
+AND COALESCE(d.host, '') = sqlc.arg(host)::citext
+AND u.deleted_at IS NULL;
+
+-- name: TrashURLsByIDs :exec
+UPDATE urls
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = ANY(sqlc.arg(ids)::bigint[])
+AND deleted_at IS NULL;
+
+-- name: ExtendURLsExpiry :exec
+UPDATE urls
+SET expired_at = GREATEST(expired_at, CURRENT_TIMESTAMP) + sqlc.arg(seconds)::bigint * INTERVAL '1 second'
+WHERE id = ANY(sqlc.arg(ids)::bigint[]);
+
+-- name: SetURLsExpiry :exec
+UPDATE urls
+SET expired_at = sqlc.arg(expired_at)
+WHERE id = ANY(sqlc.arg(ids)::bigint[]);
 
--- name: UpdateURLExpiredByShortCode :exec
+-- name: SetURLsDisabled :exec
 UPDATE urls
-SET expired_at = $1
-WHERE short_code = $2;
\ No newline at end of file
+SET disabled = sqlc.arg(disabled)
+WHERE id = ANY(sqlc.arg(ids)::bigint[]);
@@ -0,0 +1,27 @@
+-- name: ListVariantsByURLID :many
+SELECT id, url_id, destination, weight, created_at
+FROM url_variants
+WHERE url_id = $1
+ORDER BY id;
+
+-- name: CreateVariant :one
+INSERT INTO url_variants (
+    url_id,
+    destination,
+    weight
+) VALUES (
+    $1, $2, $3
+) RETURNING id, url_id, destination, weight, created_at;
+
+-- name: UpdateVariant :one
+UPDATE url_variants
+SET destination = $1,
+    weight = $2
+WHERE id = $3
+AND url_id = $4
+RETURNING id, url_id, destination, weight, created_at;
+
+-- name: DeleteVariant :execrows
+DELETE FROM url_variants
+WHERE id = $1
+AND url_id = $2;
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+// BatchURLs godoc
+// @Summary 批量操作短链接
+// @Description 对多个短链接执行同一操作，返回每个短链接的结果。extend需要duration(小时)，
+// @Description set_expiry需要expired_at，add_tag和remove_tag需要tag_id
+// @Tags URL
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param request body model.BatchURLsRequest true "批量操作请求"
+// @Success 200 {object} model.BatchURLsResponse
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/urls/batch [post]
+func (h *URLHandler) BatchURLs(c echo.Context) error {
+	var req model.BatchURLsRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	resp, err := h.urlService.BatchURLs(c.Request().Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidBatch):
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		case errors.Is(err, model.ErrTagNotFound):
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		default:
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
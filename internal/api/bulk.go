@@ -0,0 +1,250 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	bulkMaxRows  = 10000
+	bulkMaxBytes = 32 << 20 // 请求体的最大长度，避免读取过大的文件耗尽内存
+)
+
+// BulkCreateURLs godoc
+// @Summary 批量创建短链接
+// @Description 通过JSON数组或CSV文件批量创建短链接，返回每一行的结果。CSV第一行为表头，
+// @Description 列名与JSON字段名相同，original_url必填，多个标签用分号分隔，不存在的标签会自动创建
+// @Tags URL
+// @Accept json,mpfd,text/csv
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param domain query string false "所有短链接使用的已验证自定义域名"
+// @Param request body []model.BulkURLItem false "JSON格式的短链接列表"
+// @Param file formData file false "CSV或JSON文件"
+// @Success 200 {object} model.BulkCreateURLsResponse
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 413 {object} echo.HTTPError
+// @Failure 415 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/urls/bulk [post]
+func (h *URLHandler) BulkCreateURLs(c echo.Context) error {
+	req := model.BulkCreateURLsRequest{Domain: c.QueryParam("domain")}
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	items, err := readBulkItems(c)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "没有需要创建的短链接")
+	}
+	if len(items) > bulkMaxRows {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("一次最多创建%d个短链接", bulkMaxRows))
+	}
+
+	// 不合法的行直接返回结果，其余的交给service创建
+	var invalid []model.BulkURLResult
+	for _, item := range items {
+		if item.err != nil {
+			invalid = append(invalid, model.BulkURLResult{Row: item.Row, Status: model.BulkInvalid, Error: item.err.Error()})
+			continue
+		}
+		if err := c.Validate(item.BulkURLItem); err != nil {
+			invalid = append(invalid, model.BulkURLResult{Row: item.Row, Status: bulkInvalidStatus(err), Error: err.Error()})
+			continue
+		}
+		req.Items = append(req.Items, item.BulkURLItem)
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	resp, err := h.urlService.BulkCreateURLs(c.Request().Context(), req)
+	if err != nil {
+		return domainError(err)
+	}
+
+	resp.Items = append(resp.Items, invalid...)
+	resp.Failed += len(invalid)
+	sort.Slice(resp.Items, func(i, j int) bool {
+		return resp.Items[i].Row < resp.Items[j].Row
+	})
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// bulkItem 解析后的一行，err不为空表示该行的字段格式不正确
+type bulkItem struct {
+	model.BulkURLItem
+	err error
+}
+
+// readBulkItems 按Content-Type读取JSON数组、CSV请求体或上传的文件
+func readBulkItems(c echo.Context) ([]bulkItem, error) {
+	ctype := c.Request().Header.Get(echo.HeaderContentType)
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, bulkMaxBytes)
+
+	switch {
+	case strings.HasPrefix(ctype, echo.MIMEApplicationJSON):
+		return decodeBulkJSON(c.Request().Body)
+	case strings.HasPrefix(ctype, "text/csv"):
+		return decodeBulkCSV(c.Request().Body)
+	case strings.HasPrefix(ctype, echo.MIMEMultipartForm):
+		fh, err := c.FormFile("file")
+		if err != nil {
+			return nil, bulkReadError(err)
+		}
+
+		f, err := fh.Open()
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		defer f.Close()
+
+		if strings.HasSuffix(strings.ToLower(fh.Filename), ".json") {
+			return decodeBulkJSON(f)
+		}
+		return decodeBulkCSV(f)
+	default:
+		return nil, echo.NewHTTPError(http.StatusUnsupportedMediaType, "只支持JSON或CSV格式")
+	}
+}
+
+// decodeBulkJSON 逐个解码数组中的元素，超过bulkMaxRows时立即停止
+func decodeBulkJSON(r io.Reader) ([]bulkItem, error) {
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, bulkReadError(err)
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "JSON必须是短链接的数组")
+	}
+
+	var items []bulkItem
+	for dec.More() {
+		if len(items) >= bulkMaxRows {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("一次最多创建%d个短链接", bulkMaxRows))
+		}
+
+		var row model.BulkURLItem
+		if err := dec.Decode(&row); err != nil {
+			return nil, bulkReadError(err)
+		}
+		row.Row = len(items) + 1
+		items = append(items, bulkItem{BulkURLItem: row})
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, bulkReadError(err)
+	}
+
+	return items, nil
+}
+
+func decodeBulkCSV(r io.Reader) ([]bulkItem, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.As(err, new(*http.MaxBytesError)) {
+		return nil, bulkReadError(err)
+	}
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "CSV表头不正确: "+err.Error())
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	if _, ok := columns["original_url"]; !ok {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "CSV缺少original_url列")
+	}
+
+	var items []bulkItem
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, bulkReadError(err)
+		}
+		if len(items) >= bulkMaxRows {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("一次最多创建%d个短链接", bulkMaxRows))
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		item := bulkItem{BulkURLItem: model.BulkURLItem{
+			Row:         len(items) + 1,
+			OriginalURL: field("original_url"),
+			CustomCode:  field("custom_code"),
+			Title:       field("title"),
+			Notes:       field("notes"),
+		}}
+
+		if v := field("duration"); v != "" {
+			duration, err := strconv.Atoi(v)
+			if err != nil {
+				item.err = fmt.Errorf("duration不是整数: %s", v)
+			}
+			item.Duration = &duration
+		}
+
+		for _, tag := range strings.Split(field("tags"), ";") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				item.Tags = append(item.Tags, tag)
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// bulkReadError 请求体超过bulkMaxBytes时返回413，其他读取错误返回400
+func bulkReadError(err error) error {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("文件不能超过%dMB", bulkMaxBytes>>20))
+	}
+	return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+}
+
+// bulkInvalidStatus 原始URL不正确时单独标记，方便用户定位
+func bulkInvalidStatus(err error) string {
+	var errs validator.ValidationErrors
+	if errors.As(err, &errs) {
+		for _, e := range errs {
+			if e.Field() == "OriginalURL" {
+				return model.BulkInvalidURL
+			}
+		}
+	}
+	return model.BulkInvalid
+}
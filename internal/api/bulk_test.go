@@ -0,0 +1,123 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+// httpStatus 取出echo.HTTPError的状态码，其他错误返回0
+func httpStatus(err error) int {
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		return he.Code
+	}
+	return 0
+}
+
+func TestDecodeBulkJSON(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		want       []model.BulkURLItem
+		wantStatus int
+	}{
+		{
+			name: "items",
+			body: `[{"original_url":"https://a.example.com/","tags":["x"]},{"original_url":"https://b.example.com/","custom_code":"abcd"}]`,
+			want: []model.BulkURLItem{
+				{Row: 1, OriginalURL: "https://a.example.com/", Tags: []string{"x"}},
+				{Row: 2, OriginalURL: "https://b.example.com/", CustomCode: "abcd"},
+			},
+		},
+		{name: "empty array", body: `[]`},
+		{name: "not an array", body: `{"original_url":"https://a.example.com/"}`, wantStatus: http.StatusBadRequest},
+		{name: "bad item", body: `[{"original_url":1}]`, wantStatus: http.StatusBadRequest},
+		{name: "truncated", body: `[{"original_url":"https://a.example.com/"}`, wantStatus: http.StatusBadRequest},
+		{name: "too many rows", body: "[" + strings.Repeat(`{},`, bulkMaxRows) + "{}]", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := decodeBulkJSON(strings.NewReader(tt.body))
+			if status := httpStatus(err); status != tt.wantStatus {
+				t.Fatalf("decodeBulkJSON() error = %v, want status %d", err, tt.wantStatus)
+			}
+
+			var got []model.BulkURLItem
+			for _, item := range items {
+				got = append(got, item.BulkURLItem)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeBulkJSON() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeBulkCSV(t *testing.T) {
+	two := 2
+
+	tests := []struct {
+		name       string
+		body       string
+		want       []model.BulkURLItem
+		wantErrs   []bool
+		wantStatus int
+	}{
+		{
+			name: "columns in any order",
+			body: "\ufeffTitle, original_url,duration,tags\nA,https://a.example.com/,2,x; y ;\n,https://b.example.com/,,\n",
+			want: []model.BulkURLItem{
+				{Row: 1, OriginalURL: "https://a.example.com/", Title: "A", Duration: &two, Tags: []string{"x", "y"}},
+				{Row: 2, OriginalURL: "https://b.example.com/"},
+			},
+			wantErrs: []bool{false, false},
+		},
+		{
+			name:     "bad duration",
+			body:     "original_url,duration\nhttps://a.example.com/,soon\n",
+			want:     []model.BulkURLItem{{Row: 1, OriginalURL: "https://a.example.com/", Duration: new(int)}},
+			wantErrs: []bool{true},
+		},
+		{name: "missing original_url", body: "url,title\nhttps://a.example.com/,A\n", wantStatus: http.StatusBadRequest},
+		{name: "empty", body: "", wantStatus: http.StatusBadRequest},
+		{name: "too many rows", body: "original_url\n" + strings.Repeat("https://a.example.com/\n", bulkMaxRows+1), wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := decodeBulkCSV(strings.NewReader(tt.body))
+			if status := httpStatus(err); status != tt.wantStatus {
+				t.Fatalf("decodeBulkCSV() error = %v, want status %d", err, tt.wantStatus)
+			}
+
+			var got []model.BulkURLItem
+			var gotErrs []bool
+			for _, item := range items {
+				got = append(got, item.BulkURLItem)
+				gotErrs = append(gotErrs, item.err != nil)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeBulkCSV() = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotErrs, tt.wantErrs) {
+				t.Errorf("decodeBulkCSV() row errors = %v, want %v", gotErrs, tt.wantErrs)
+			}
+		})
+	}
+}
+
+func TestDecodeBulkTooLarge(t *testing.T) {
+	body := http.MaxBytesReader(nil, io.NopCloser(strings.NewReader(`[{"original_url":"https://a.example.com/"}]`)), 10)
+
+	if _, err := decodeBulkJSON(body); httpStatus(err) != http.StatusRequestEntityTooLarge {
+		t.Errorf("decodeBulkJSON() error = %v, want status %d", err, http.StatusRequestEntityTooLarge)
+	}
+}
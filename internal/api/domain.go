@@ -0,0 +1,146 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+// GetDomains godoc
+// @Summary 获取自定义域名
+// @Description 获取当前用户添加的所有自定义域名及其验证状态
+// @Tags 自定义域名
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Success 200 {object} model.GetDomainsResponse
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/domains [get]
+func (h *URLHandler) GetDomains(c echo.Context) error {
+	userID, _ := c.Get("userID").(int)
+
+	resp, err := h.urlService.GetDomains(c.Request().Context(), userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CreateDomain godoc
+// @Summary 添加自定义域名
+// @Description 添加自定义域名，返回需要配置的DNS TXT记录，验证通过后才能在该域名下创建短链接
+// @Tags 自定义域名
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param request body model.CreateDomainRequest true "添加自定义域名请求"
+// @Success 201 {object} model.Domain
+// @Failure 400 {object} echo.HTTPError
+// @Failure 409 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/domains [post]
+func (h *URLHandler) CreateDomain(c echo.Context) error {
+	var req model.CreateDomainRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	resp, err := h.urlService.CreateDomain(c.Request().Context(), req)
+	if err != nil {
+		return domainError(err)
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// VerifyDomain godoc
+// @Summary 验证自定义域名
+// @Description 查询域名的DNS TXT记录，记录正确时将域名标记为已验证
+// @Tags 自定义域名
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param id path int true "域名ID"
+// @Success 200 {object} model.Domain
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 409 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/domains/{id}/verify [post]
+func (h *URLHandler) VerifyDomain(c echo.Context) error {
+	var req model.VerifyDomainRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	resp, err := h.urlService.VerifyDomain(c.Request().Context(), req)
+	if err != nil {
+		return domainError(err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// DeleteDomain godoc
+// @Summary 删除自定义域名
+// @Description 删除自定义域名，域名下还有短链接(包括回收站中的)时无法删除
+// @Tags 自定义域名
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param id path int true "域名ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 409 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/domains/{id} [delete]
+func (h *URLHandler) DeleteDomain(c echo.Context) error {
+	var req model.DeleteDomainRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	if err := h.urlService.DeleteDomain(c.Request().Context(), req); err != nil {
+		return domainError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func domainError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrDomainNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, model.ErrDomainTaken), errors.Is(err, model.ErrDomainInUse):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, model.ErrDomainNotVerified), errors.Is(err, model.ErrDomainVerifyFailed), errors.Is(err, model.ErrInvalidDomain):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+// exportFlushRows 每写入多少行刷新一次响应
+const exportFlushRows = 500
+
+var exportCSVHeader = []string{"short_url", "original_url", "short_code", "domain", "title", "notes", "tags", "is_custom", "views", "created_at", "expired_at"}
+
+// ExportURLs godoc
+// @Summary 导出短链接
+// @Description 以流的形式导出当前用户的所有短链接及浏览量，支持CSV、JSON数组和NDJSON。CSV中多个标签用分号分隔
+// @Tags URL
+// @Produce text/csv,json,application/x-ndjson
+// @Param Authorization header string true "Bearer JWT token"
+// @Param format query string false "导出格式" Enums(csv, json, ndjson) default(csv)
+// @Success 200 {array} model.ExportedURL
+// @Failure 400 {object} echo.HTTPError
+// @Router /api/urls/export [get]
+func (h *URLHandler) ExportURLs(c echo.Context) error {
+	var req model.ExportURLsRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if req.Format == "" {
+		req.Format = model.ExportCSV
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	// 导出可能持续较长时间，取消服务器的写超时
+	if err := http.NewResponseController(c.Response()).SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("failed to clear write deadline: %v", err)
+	}
+
+	w := c.Response()
+	switch req.Format {
+	case model.ExportJSON:
+		w.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	case model.ExportNDJSON:
+		w.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+	default:
+		w.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	}
+	w.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="urls-%s.%s"`, time.Now().Format("20060102"), req.Format))
+	w.WriteHeader(http.StatusOK)
+
+	exporter := newURLExporter(w, req.Format)
+	if err := exporter.begin(); err != nil {
+		log.Printf("failed to export urls of user %d: %v", req.UserID, err)
+		return nil
+	}
+
+	rows := 0
+	err := h.urlService.ExportURLs(c.Request().Context(), req.UserID, func(url model.ExportedURL) error {
+		if err := exporter.write(url); err != nil {
+			return err
+		}
+
+		rows++
+		if rows%exportFlushRows == 0 {
+			return exporter.flush()
+		}
+		return nil
+	})
+	if err != nil {
+		// 响应已经开始发送，无法再返回错误状态码，不完整的内容由客户端发现
+		log.Printf("failed to export urls of user %d: %v", req.UserID, err)
+		return nil
+	}
+
+	if err := exporter.end(); err != nil {
+		log.Printf("failed to export urls of user %d: %v", req.UserID, err)
+	}
+
+	return nil
+}
+
+// urlExporter 按格式逐条写出短链接
+type urlExporter struct {
+	w      *echo.Response
+	format string
+	csv    *csv.Writer
+	json   *json.Encoder
+	count  int
+}
+
+func newURLExporter(w *echo.Response, format string) *urlExporter {
+	e := &urlExporter{w: w, format: format}
+	if format == model.ExportCSV {
+		e.csv = csv.NewWriter(w)
+	} else {
+		e.json = json.NewEncoder(w)
+	}
+	return e
+}
+
+func (e *urlExporter) begin() error {
+	switch e.format {
+	case model.ExportCSV:
+		return e.csv.Write(exportCSVHeader)
+	case model.ExportJSON:
+		_, err := e.w.Write([]byte("["))
+		return err
+	}
+	return nil
+}
+
+func (e *urlExporter) write(url model.ExportedURL) error {
+	defer func() { e.count++ }()
+
+	switch e.format {
+	case model.ExportCSV:
+		return e.csv.Write([]string{
+			url.ShortURL,
+			csvCell(url.OriginalURL),
+			url.ShortCode,
+			url.Domain,
+			csvCell(url.Title),
+			csvCell(url.Notes),
+			csvCell(strings.Join(url.Tags, ";")),
+			strconv.FormatBool(url.IsCustom),
+			strconv.FormatUint(uint64(url.Views), 10),
+			url.CreatedAt.Format(time.RFC3339),
+			url.ExpiredAt.Format(time.RFC3339),
+		})
+	case model.ExportJSON:
+		if e.count > 0 {
+			if _, err := e.w.Write([]byte(",")); err != nil {
+				return err
+			}
+		}
+	}
+	// Encode会在末尾加换行，NDJSON正好每行一条
+	return e.json.Encode(url)
+}
+
+// csvCell 用户填写的内容以=、+、-、@等开头时在电子表格中会被当作公式执行，加上'作为文本
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func (e *urlExporter) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	e.w.Flush()
+	return nil
+}
+
+func (e *urlExporter) end() error {
+	if e.format == model.ExportJSON {
+		if _, err := e.w.Write([]byte("]")); err != nil {
+			return err
+		}
+	}
+	return e.flush()
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+func TestCSVCell(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "empty", s: "", want: ""},
+		{name: "plain", s: "hello", want: "hello"},
+		{name: "url", s: "https://example.com/?a=1", want: "https://example.com/?a=1"},
+		{name: "formula", s: "=HYPERLINK(\"http://evil\")", want: "'=HYPERLINK(\"http://evil\")"},
+		{name: "plus", s: "+1", want: "'+1"},
+		{name: "minus", s: "-1+2", want: "'-1+2"},
+		{name: "at", s: "@SUM(A1)", want: "'@SUM(A1)"},
+		{name: "tab", s: "\t=1", want: "'\t=1"},
+		{name: "carriage return", s: "\r=1", want: "'\r=1"},
+		{name: "formula not at start", s: "a=b", want: "a=b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := csvCell(tt.s); got != tt.want {
+				t.Errorf("csvCell(%q) = %q, want %q", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURLExporter(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	urls := []model.ExportedURL{
+		{ShortURL: "https://s.example/abcd", OriginalURL: "https://a.example.com/", ShortCode: "abcd", Title: "=cmd", Tags: []string{"x", "y"}, Views: 3, CreatedAt: at, ExpiredAt: at},
+		{ShortURL: "https://go.example.com/efgh", OriginalURL: "https://b.example.com/", ShortCode: "efgh", Domain: "go.example.com", Tags: []string{}, CreatedAt: at, ExpiredAt: at},
+	}
+
+	export := func(t *testing.T, format string) string {
+		rec := httptest.NewRecorder()
+		exporter := newURLExporter(echo.NewResponse(rec, echo.New()), format)
+		if err := exporter.begin(); err != nil {
+			t.Fatalf("begin() error = %v", err)
+		}
+		for _, url := range urls {
+			if err := exporter.write(url); err != nil {
+				t.Fatalf("write() error = %v", err)
+			}
+		}
+		if err := exporter.end(); err != nil {
+			t.Fatalf("end() error = %v", err)
+		}
+		return rec.Body.String()
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		lines := strings.Split(strings.TrimSpace(export(t, model.ExportCSV)), "\n")
+		want := []string{
+			strings.Join(exportCSVHeader, ","),
+			"https://s.example/abcd,https://a.example.com/,abcd,,'=cmd,,x;y,false,3,2024-01-02T03:04:05Z,2024-01-02T03:04:05Z",
+			"https://go.example.com/efgh,https://b.example.com/,efgh,go.example.com,,,,false,0,2024-01-02T03:04:05Z,2024-01-02T03:04:05Z",
+		}
+		if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+			t.Errorf("csv export =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var got []model.ExportedURL
+		if err := json.Unmarshal([]byte(export(t, model.ExportJSON)), &got); err != nil {
+			t.Fatalf("json export is not an array: %v", err)
+		}
+		if len(got) != 2 || got[0].Title != "=cmd" {
+			t.Errorf("json export = %+v, want 2 urls with raw titles", got)
+		}
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		lines := strings.Split(strings.TrimSpace(export(t, model.ExportNDJSON)), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("ndjson export has %d lines, want 2", len(lines))
+		}
+		for _, line := range lines {
+			var url model.ExportedURL
+			if err := json.Unmarshal([]byte(line), &url); err != nil {
+				t.Errorf("ndjson line %q: %v", line, err)
+			}
+		}
+	})
+}
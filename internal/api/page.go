@@ -0,0 +1,107 @@
+package api
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 重定向过程中需要直接返回给访问者的HTML页面
+
+var passwordPage = template.Must(template.New("password").Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>需要密码</title>
+</head>
+<body style="font-family: sans-serif; max-width: 420px; margin: 15vh auto; padding: 0 16px;">
+<h2>此链接受密码保护</h2>
+{{if .Error}}<p style="color: #c00;">{{.Error}}</p>{{end}}
+<form method="post">
+<input type="password" name="password" placeholder="请输入密码" required autofocus style="width: 100%; padding: 8px; box-sizing: border-box;">
+<button type="submit" style="margin-top: 12px; padding: 8px 16px;">访问</button>
+</form>
+</body>
+</html>
+`))
+
+type passwordPageData struct {
+	Error string
+}
+
+var defaultNotActivePage = template.Must(template.New("not_active").Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>尚未开放</title>
+</head>
+<body style="font-family: sans-serif; max-width: 420px; margin: 15vh auto; padding: 0 16px;">
+<h2>此链接尚未开放</h2>
+<p>请稍后再试。</p>
+</body>
+</html>
+`))
+
+var previewPage = template.Must(template.New("preview").Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>链接预览</title>
+</head>
+<body style="font-family: sans-serif; max-width: 560px; margin: 15vh auto; padding: 0 16px;">
+<h2>即将访问以下链接</h2>
+<table style="border-collapse: collapse; width: 100%;">
+<tr><th style="text-align: left; padding: 6px 12px 6px 0; white-space: nowrap;">目标地址</th><td style="word-break: break-all;">
+{{- if .Destination}}{{.Destination}}{{else if .Protected}}受密码保护，输入密码后可见{{else}}访问次数有限，访问后可见{{end -}}
+</td></tr>
+<tr><th style="text-align: left; padding: 6px 12px 6px 0; white-space: nowrap;">创建时间</th><td>{{if .CreatedAt.IsZero}}-{{else}}{{.CreatedAt.UTC.Format "2006-01-02 15:04 MST"}}{{end}}</td></tr>
+<tr><th style="text-align: left; padding: 6px 12px 6px 0; white-space: nowrap;">过期时间</th><td>{{if .ExpiredAt.IsZero}}-{{else}}{{.ExpiredAt.UTC.Format "2006-01-02 15:04 MST"}}{{end}}</td></tr>
+<tr><th style="text-align: left; padding: 6px 12px 6px 0; white-space: nowrap;">访问次数</th><td>{{.Views}}</td></tr>
+</table>
+<p style="margin-top: 24px;"><a href="/{{.ShortCode}}?confirm=1" rel="noreferrer">继续访问</a></p>
+</body>
+</html>
+`))
+
+// metaRedirectPage 用于需要隐藏来源的短链接，页面本身和跳转都不发送Referer。
+// 只使用meta refresh，不把地址放进脚本中
+var metaRedirectPage = template.Must(template.New("meta_redirect").Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="referrer" content="no-referrer">
+<meta name="robots" content="noindex">
+<meta http-equiv="refresh" content="0; url={{.}}">
+<title>正在跳转</title>
+</head>
+<body style="font-family: sans-serif; max-width: 420px; margin: 15vh auto; padding: 0 16px;">
+<p>正在跳转，如果没有自动跳转请<a href="{{.}}" rel="noreferrer">点击这里</a>。</p>
+</body>
+</html>
+`))
+
+// loadPage 从文件加载自定义页面，未配置时使用内置页面
+func loadPage(path string, fallback *template.Template) (*template.Template, error) {
+	if path == "" {
+		return fallback, nil
+	}
+
+	return template.ParseFiles(path)
+}
+
+func renderPage(c echo.Context, code int, page *template.Template, data any) error {
+	var buf bytes.Buffer
+	if err := page.Execute(&buf, data); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.HTMLBlob(code, buf.Bytes())
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+// GetQRCode godoc
+// @Summary 生成短链接的二维码
+// @Description 生成PNG或SVG格式的二维码，可以设置尺寸、留白、纠错等级、颜色和中间的logo
+// @Tags URL
+// @Produce png
+// @Produce image/svg+xml
+// @Param Authorization header string true "Bearer JWT token"
+// @Param code path string true "短链接代码"
+// @Param domain query string false "短链接所在的自定义域名，为空表示默认域名"
+// @Param format query string false "图片格式 png/svg" default(png)
+// @Param size query int false "图片边长(像素), 64-2048" default(256)
+// @Param margin query int false "四周留白的模块数, 0-16" default(4)
+// @Param level query string false "纠错等级 L/M/Q/H, 使用logo时固定为H" default(M)
+// @Param fg query string false "前景色 RRGGBB" default(000000)
+// @Param bg query string false "背景色 RRGGBB" default(ffffff)
+// @Param logo query bool false "是否在中间绘制配置的logo" default(false)
+// @Success 200 {file} file "二维码图片"
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/url/{code}/qr [get]
+func (h *URLHandler) GetQRCode(c echo.Context) error {
+	var req model.GetQRCodeRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if req.Format == "" {
+		req.Format = "png"
+	}
+	if req.Size == 0 {
+		req.Size = 256
+	}
+	if req.Margin == nil {
+		margin := 4
+		req.Margin = &margin
+	}
+	if req.Level == "" {
+		req.Level = "M"
+	}
+	if req.Foreground == "" {
+		req.Foreground = "000000"
+	}
+	if req.Background == "" {
+		req.Background = "ffffff"
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	qr, err := h.urlService.GetQRCode(c.Request().Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrURLNotFound):
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		case errors.Is(err, model.ErrInvalidQRCode):
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		default:
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return c.Blob(http.StatusOK, qr.ContentType, qr.Data)
+}
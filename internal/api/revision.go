@@ -0,0 +1,90 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+// GetHistory godoc
+// @Summary 获取短链接的历史版本
+// @Description 返回短链接目标地址、标题和备注的所有历史版本，最新的在前
+// @Tags URL
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param code path string true "短链接代码"
+// @Param domain query string false "短链接所在的自定义域名，为空表示默认域名"
+// @Success 200 {object} model.GetHistoryResponse
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/url/{code}/history [get]
+func (h *URLHandler) GetHistory(c echo.Context) error {
+	var req model.GetHistoryRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	resp, err := h.urlService.GetHistory(c.Request().Context(), req)
+	if err != nil {
+		return revisionError(err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// RollbackURL godoc
+// @Summary 回滚短链接
+// @Description 将短链接的目标地址、标题和备注恢复为指定的历史版本，回滚本身会记录为一个新版本
+// @Tags URL
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param code path string true "短链接代码"
+// @Param domain query string false "短链接所在的自定义域名，为空表示默认域名"
+// @Param id path int true "历史版本ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/url/{code}/history/{id}/rollback [post]
+func (h *URLHandler) RollbackURL(c echo.Context) error {
+	var req model.RollbackURLRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	req.Domain = c.QueryParam("domain")
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	if err := h.urlService.RollbackURL(c.Request().Context(), req); err != nil {
+		return revisionError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func revisionError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrURLNotFound), errors.Is(err, model.ErrRevisionNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+// GetRules godoc
+// @Summary 获取短链接的定向规则
+// @Description 按匹配顺序返回短链接的所有定向规则
+// @Tags 定向规则
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param code path string true "短链接代码"
+// @Param domain query string false "短链接所在的自定义域名，为空表示默认域名"
+// @Success 200 {object} model.GetRulesResponse
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/url/{code}/rules [get]
+func (h *URLHandler) GetRules(c echo.Context) error {
+	var req model.GetRulesRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	resp, err := h.urlService.GetRules(c.Request().Context(), req)
+	if err != nil {
+		return ruleError(err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CreateRule godoc
+// @Summary 添加定向规则
+// @Description 为短链接添加一条定向规则，条件类型为 country/os/device/language/weekday/time
+// @Tags 定向规则
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param code path string true "短链接代码"
+// @Param domain query string false "短链接所在的自定义域名，为空表示默认域名"
+// @Param request body model.CreateRuleRequest true "添加定向规则请求"
+// @Success 201 {object} model.Rule
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/url/{code}/rules [post]
+func (h *URLHandler) CreateRule(c echo.Context) error {
+	var req model.CreateRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	req.Domain = c.QueryParam("domain")
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	rule, err := h.urlService.CreateRule(c.Request().Context(), req)
+	if err != nil {
+		return ruleError(err)
+	}
+
+	return c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateRule godoc
+// @Summary 修改定向规则
+// @Description 修改短链接的一条定向规则
+// @Tags 定向规则
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param code path string true "短链接代码"
+// @Param domain query string false "短链接所在的自定义域名，为空表示默认域名"
+// @Param id path int true "规则ID"
+// @Param request body model.UpdateRuleRequest true "修改定向规则请求"
+// @Success 200 {object} model.Rule
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/url/{code}/rules/{id} [put]
+func (h *URLHandler) UpdateRule(c echo.Context) error {
+	var req model.UpdateRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	req.Domain = c.QueryParam("domain")
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	rule, err := h.urlService.UpdateRule(c.Request().Context(), req)
+	if err != nil {
+		return ruleError(err)
+	}
+
+	return c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule godoc
+// @Summary 删除定向规则
+// @Description 删除短链接的一条定向规则
+// @Tags 定向规则
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param code path string true "短链接代码"
+// @Param domain query string false "短链接所在的自定义域名，为空表示默认域名"
+// @Param id path int true "规则ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/url/{code}/rules/{id} [delete]
+func (h *URLHandler) DeleteRule(c echo.Context) error {
+	var req model.DeleteRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	if err := h.urlService.DeleteRule(c.Request().Context(), req); err != nil {
+		return ruleError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func ruleError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrURLNotFound), errors.Is(err, model.ErrRuleNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, model.ErrInvalidRule), errors.Is(err, model.ErrInvalidTimezone):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+// GetStats godoc
+// @Summary 获取短链接的点击统计
+// @Description 按小时、天或周统计指定时间范围内的点击次数
+// @Tags 统计
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param code path string true "短链接代码"
+// @Param domain query string false "短链接所在的自定义域名，为空表示默认域名"
+// @Param interval query string false "统计粒度 hour/day/week" default(day)
+// @Param from query string false "开始时间 RFC3339, 默认为7天前"
+// @Param to query string false "结束时间 RFC3339, 默认为当前时间"
+// @Param tz query string false "时区, 例如 Asia/Shanghai" default(UTC)
+// @Success 200 {object} model.GetStatsResponse
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/url/{code}/stats [get]
+func (h *URLHandler) GetStats(c echo.Context) error {
+	var req model.GetStatsRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if req.Interval == "" {
+		req.Interval = "day"
+	}
+
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	resp, err := h.urlService.GetStats(c.Request().Context(), req)
+	if err != nil {
+		return statsError(err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetGeoStats godoc
+// @Summary 获取短链接点击的地理分布
+// @Description 按国家和城市统计指定时间范围内的点击次数
+// @Tags 统计
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param code path string true "短链接代码"
+// @Param domain query string false "短链接所在的自定义域名，为空表示默认域名"
+// @Param from query string false "开始时间 RFC3339, 默认为7天前"
+// @Param to query string false "结束时间 RFC3339, 默认为当前时间"
+// @Success 200 {object} model.GetGeoStatsResponse
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/url/{code}/stats/geo [get]
+func (h *URLHandler) GetGeoStats(c echo.Context) error {
+	var req model.GetBreakdownRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	resp, err := h.urlService.GetGeoStats(c.Request().Context(), req)
+	if err != nil {
+		return statsError(err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetDeviceStats godoc
+// @Summary 获取短链接点击的设备分布
+// @Description 按设备类型、操作系统、浏览器以及是否为机器人统计点击次数
+// @Tags 统计
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param code path string true "短链接代码"
+// @Param domain query string false "短链接所在的自定义域名，为空表示默认域名"
+// @Param from query string false "开始时间 RFC3339, 默认为7天前"
+// @Param to query string false "结束时间 RFC3339, 默认为当前时间"
+// @Success 200 {object} model.GetDeviceStatsResponse
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/url/{code}/stats/devices [get]
+func (h *URLHandler) GetDeviceStats(c echo.Context) error {
+	var req model.GetBreakdownRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	resp, err := h.urlService.GetDeviceStats(c.Request().Context(), req)
+	if err != nil {
+		return statsError(err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetUserDeviceStats godoc
+// @Summary 获取用户所有短链接点击的设备分布
+// @Description 汇总当前用户所有短链接的设备类型、操作系统、浏览器分布
+// @Tags 统计
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param from query string false "开始时间 RFC3339, 默认为7天前"
+// @Param to query string false "结束时间 RFC3339, 默认为当前时间"
+// @Success 200 {object} model.GetDeviceStatsResponse
+// @Failure 400 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/stats/devices [get]
+func (h *URLHandler) GetUserDeviceStats(c echo.Context) error {
+	var req model.GetUserBreakdownRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	resp, err := h.urlService.GetUserDeviceStats(c.Request().Context(), req)
+	if err != nil {
+		return statsError(err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func statsError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrURLNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, model.ErrInvalidTimezone), errors.Is(err, model.ErrInvalidStatsRange):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
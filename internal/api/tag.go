@@ -0,0 +1,218 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+// GetTags godoc
+// @Summary 获取标签
+// @Description 获取当前用户的所有标签，按名称排序
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Success 200 {object} model.GetTagsResponse
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/tags [get]
+func (h *URLHandler) GetTags(c echo.Context) error {
+	userID, _ := c.Get("userID").(int)
+
+	resp, err := h.urlService.GetTags(c.Request().Context(), userID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CreateTag godoc
+// @Summary 创建标签
+// @Description 创建标签，同一用户下标签名称不能重复(忽略大小写)
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param request body model.CreateTagRequest true "创建标签请求"
+// @Success 201 {object} model.Tag
+// @Failure 400 {object} echo.HTTPError
+// @Failure 409 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/tags [post]
+func (h *URLHandler) CreateTag(c echo.Context) error {
+	var req model.CreateTagRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	resp, err := h.urlService.CreateTag(c.Request().Context(), req)
+	if err != nil {
+		return tagError(err)
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// RenameTag godoc
+// @Summary 重命名标签
+// @Description 修改标签的名称，已添加该标签的短链接同时生效
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param id path int true "标签ID"
+// @Param request body model.RenameTagRequest true "重命名标签请求"
+// @Success 200 {object} model.Tag
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 409 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/tags/{id} [patch]
+func (h *URLHandler) RenameTag(c echo.Context) error {
+	var req model.RenameTagRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	resp, err := h.urlService.RenameTag(c.Request().Context(), req)
+	if err != nil {
+		return tagError(err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// DeleteTag godoc
+// @Summary 删除标签
+// @Description 删除标签并从所有短链接上移除，短链接本身不受影响
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param id path int true "标签ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/tags/{id} [delete]
+func (h *URLHandler) DeleteTag(c echo.Context) error {
+	var req model.DeleteTagRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	if err := h.urlService.DeleteTag(c.Request().Context(), req); err != nil {
+		return tagError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// AttachTag godoc
+// @Summary 为短链接添加标签
+// @Description 为短链接添加标签，重复添加不会报错
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param code path string true "短链接代码"
+// @Param domain query string false "短链接所在的自定义域名，为空表示默认域名"
+// @Param id path int true "标签ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/url/{code}/tags/{id} [put]
+func (h *URLHandler) AttachTag(c echo.Context) error {
+	var req model.URLTagRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	req.Domain = c.QueryParam("domain")
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	if err := h.urlService.AttachTag(c.Request().Context(), req); err != nil {
+		return tagError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DetachTag godoc
+// @Summary 移除短链接的标签
+// @Description 从短链接上移除标签，标签本身不会被删除
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param code path string true "短链接代码"
+// @Param domain query string false "短链接所在的自定义域名，为空表示默认域名"
+// @Param id path int true "标签ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/url/{code}/tags/{id} [delete]
+func (h *URLHandler) DetachTag(c echo.Context) error {
+	var req model.URLTagRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	if err := h.urlService.DetachTag(c.Request().Context(), req); err != nil {
+		return tagError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func tagError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrURLNotFound), errors.Is(err, model.ErrTagNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, model.ErrTagExists):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, model.ErrInvalidTag):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+// GetTrash godoc
+// @Summary 获取回收站
+// @Description 分页获取当前用户回收站中的短链接，最近删除的在前
+// @Tags 回收站
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param page query int false "页码" default(1)
+// @Param size query int false "每页数量" default(10)
+// @Success 200 {object} model.GetTrashResponse
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/trash [get]
+func (h *URLHandler) GetTrash(c echo.Context) error {
+	userID, _ := c.Get("userID").(int)
+
+	var req model.GetTrashRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if req.Page == 0 {
+		req.Page = 1
+	}
+
+	if req.Size == 0 {
+		req.Size = 10
+	}
+
+	req.UserID = userID
+
+	resp, err := h.urlService.GetTrash(c.Request().Context(), req)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// RestoreURL godoc
+// @Summary 恢复短链接
+// @Description 将回收站中的短链接恢复，恢复后立即可以访问
+// @Tags 回收站
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param code path string true "短链接代码"
+// @Param domain query string false "短链接所在的自定义域名，为空表示默认域名"
+// @Success 204 "No Content"
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/trash/{code}/restore [post]
+func (h *URLHandler) RestoreURL(c echo.Context) error {
+	var req model.RestoreURLRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	req.Domain = c.QueryParam("domain")
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	if err := h.urlService.RestoreURL(c.Request().Context(), req); err != nil {
+		return trashError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// PurgeURL godoc
+// @Summary 永久删除短链接
+// @Description 永久删除回收站中的短链接及其统计数据，删除后无法恢复
+// @Tags 回收站
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param code path string true "短链接代码"
+// @Param domain query string false "短链接所在的自定义域名，为空表示默认域名"
+// @Success 204 "No Content"
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/trash/{code} [delete]
+func (h *URLHandler) PurgeURL(c echo.Context) error {
+	var req model.PurgeURLRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	if err := h.urlService.PurgeURL(c.Request().Context(), req); err != nil {
+		return trashError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func trashError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrURLNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
@@ -2,45 +2,118 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"html/template"
 	"log"
 	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/aeilang/urlshortener/config"
 	"github.com/aeilang/urlshortener/internal/model"
 	"github.com/labstack/echo/v4"
 )
 
 type URLServicer interface {
-	CreateURL(ctx context.Context, req model.CreateURLRequest) (shortURL string, err error)
-	GetURL(ctx context.Context, shortCode string) (originalURL string, err error)
-	IncreViews(ctx context.Context, shortCode string) error
+	CreateURL(ctx context.Context, req model.CreateURLRequest) (*model.CreateURLResponse, error)
+	GetURL(ctx context.Context, host, shortCode string, visitor model.Visitor) (*model.URL, error)
+	UnlockURL(ctx context.Context, host, shortCode, password string, visitor model.Visitor) (*model.URL, error)
+	PreviewURL(ctx context.Context, url *model.URL) (*model.URLPreview, error)
+	GetQRCode(ctx context.Context, req model.GetQRCodeRequest) (*model.QRCode, error)
+	ConsumeClick(ctx context.Context, url *model.URL, click model.Click) error
+	RecordClick(ctx context.Context, click model.Click) error
 	GetURLs(ctx context.Context, req model.GetURLsRequest) (*model.GetURLsResponse, error)
-	DeleteURL(ctx context.Context, shortCode string) error
-	UpdateURLDuration(ctx context.Context, req model.UpdateURLDurationReq) error
+	DeleteURL(ctx context.Context, req model.DeleteURLRequest) error
+	GetTrash(ctx context.Context, req model.GetTrashRequest) (*model.GetTrashResponse, error)
+	RestoreURL(ctx context.Context, req model.RestoreURLRequest) error
+	PurgeURL(ctx context.Context, req model.PurgeURLRequest) error
+	UpdateURL(ctx context.Context, req model.UpdateURLRequest) error
+	GetHistory(ctx context.Context, req model.GetHistoryRequest) (*model.GetHistoryResponse, error)
+	RollbackURL(ctx context.Context, req model.RollbackURLRequest) error
+	GetStats(ctx context.Context, req model.GetStatsRequest) (*model.GetStatsResponse, error)
+	GetGeoStats(ctx context.Context, req model.GetBreakdownRequest) (*model.GetGeoStatsResponse, error)
+	GetDeviceStats(ctx context.Context, req model.GetBreakdownRequest) (*model.GetDeviceStatsResponse, error)
+	GetUserDeviceStats(ctx context.Context, req model.GetUserBreakdownRequest) (*model.GetDeviceStatsResponse, error)
+	GetRules(ctx context.Context, req model.GetRulesRequest) (*model.GetRulesResponse, error)
+	CreateRule(ctx context.Context, req model.CreateRuleRequest) (*model.Rule, error)
+	UpdateRule(ctx context.Context, req model.UpdateRuleRequest) (*model.Rule, error)
+	DeleteRule(ctx context.Context, req model.DeleteRuleRequest) error
+	GetVariants(ctx context.Context, req model.GetVariantsRequest) (*model.GetVariantsResponse, error)
+	CreateVariant(ctx context.Context, req model.CreateVariantRequest) (*model.Variant, error)
+	UpdateVariant(ctx context.Context, req model.UpdateVariantRequest) (*model.Variant, error)
+	DeleteVariant(ctx context.Context, req model.DeleteVariantRequest) error
+	GetDomains(ctx context.Context, userID int) (*model.GetDomainsResponse, error)
+	CreateDomain(ctx context.Context, req model.CreateDomainRequest) (*model.Domain, error)
+	VerifyDomain(ctx context.Context, req model.VerifyDomainRequest) (*model.Domain, error)
+	DeleteDomain(ctx context.Context, req model.DeleteDomainRequest) error
+	GetTags(ctx context.Context, userID int) (*model.GetTagsResponse, error)
+	CreateTag(ctx context.Context, req model.CreateTagRequest) (*model.Tag, error)
+	RenameTag(ctx context.Context, req model.RenameTagRequest) (*model.Tag, error)
+	DeleteTag(ctx context.Context, req model.DeleteTagRequest) error
+	AttachTag(ctx context.Context, req model.URLTagRequest) error
+	DetachTag(ctx context.Context, req model.URLTagRequest) error
+	BulkCreateURLs(ctx context.Context, req model.BulkCreateURLsRequest) (*model.BulkCreateURLsResponse, error)
+	ExportURLs(ctx context.Context, userID int, fn func(model.ExportedURL) error) error
+	BatchURLs(ctx context.Context, req model.BatchURLsRequest) (*model.BatchURLsResponse, error)
 }
 
+var redirectStatus = map[string]int{
+	model.RedirectMovedPermanently: http.StatusMovedPermanently,
+	model.RedirectFound:            http.StatusFound,
+	model.RedirectTemporary:        http.StatusTemporaryRedirect,
+	model.RedirectPermanent:        http.StatusPermanentRedirect,
+}
+
+// variantCookie 记录访客分配到的A/B版本，路径限定为短链接本身
+const (
+	variantCookie       = "variant"
+	variantCookieMaxAge = 30 * 24 * 60 * 60
+)
+
 // URLHandler 处理URL相关的HTTP请求
 type URLHandler struct {
-	urlService URLServicer
+	urlService    URLServicer
+	notActivePage *template.Template
+	redirectType  string
 }
 
 // NewURLHandler 创建新的URL处理器
-func NewURLHandler(urlService URLServicer) *URLHandler {
-	return &URLHandler{
-		urlService: urlService,
+func NewURLHandler(urlService URLServicer, cfg config.AppConfig) (*URLHandler, error) {
+	notActivePage, err := loadPage(cfg.NotActivePage, defaultNotActivePage)
+	if err != nil {
+		return nil, err
+	}
+
+	redirectType := cfg.RedirectType
+	if redirectType == "" {
+		redirectType = model.RedirectFound
 	}
+	if _, ok := redirectStatus[redirectType]; !ok && redirectType != model.RedirectMeta {
+		return nil, fmt.Errorf("不支持的重定向方式: %s", redirectType)
+	}
+
+	return &URLHandler{
+		urlService:    urlService,
+		notActivePage: notActivePage,
+		redirectType:  redirectType,
+	}, nil
 }
 
 // CreateURL godoc
 // @Summary 创建短链接
-// @Description 将长URL转换为短URL
+// @Description 将长URL转换为短URL。未指定有效期、标题、备注等设置且已有同一目标地址的可用短链接时返回该短链接，force_new为true时总是创建新的
 // @Tags URL
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer JWT token"
 // @Param request body model.CreateURLRequest true "创建短链接请求"
+// @Success 200 {object} model.CreateURLResponse
 // @Success 201 {object} model.CreateURLResponse
 // @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
 // @Failure 500 {object} echo.HTTPError
 // @Router /api/url [post]
 func (h *URLHandler) CreateURL(c echo.Context) error {
@@ -55,13 +128,16 @@ func (h *URLHandler) CreateURL(c echo.Context) error {
 	userID, _ := c.Get("userID").(int)
 	req.UserID = userID
 
-	shortURL, err := h.urlService.CreateURL(c.Request().Context(), req)
+	resp, err := h.urlService.CreateURL(c.Request().Context(), req)
+	if errors.Is(err, model.ErrInvalidURL) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return domainError(err)
 	}
 
-	resp := model.CreateURLResponse{
-		ShortURL: shortURL,
+	if resp.Existing {
+		return c.JSON(http.StatusOK, resp)
 	}
 
 	return c.JSON(http.StatusCreated, resp)
@@ -69,42 +145,258 @@ func (h *URLHandler) CreateURL(c echo.Context) error {
 
 // RedirectURL godoc
 // @Summary 重定向到原始URL
-// @Description 通过短链接代码重定向到原始URL
+// @Description 通过短链接代码重定向到原始URL或命中的定向规则的地址, 受密码保护的短链接返回输入密码的页面
 // @Tags URL
 // @Accept json
 // @Produce json
 // @Param code path string true "短链接代码"
-// @Success 301 {string} string "重定向到原始URL"
+// @Success 302 {string} string "重定向到原始URL, 状态码为短链接设置的301/302/307/308"
+// @Success 200 {string} string "输入密码的页面、预览页或meta refresh跳转页面"
+// @Failure 403 {string} string "尚未生效的页面"
+// @Failure 404 {object} echo.HTTPError
+// @Failure 410 {object} echo.HTTPError
 // @Failure 500 {object} echo.HTTPError
 // @Router /{code} [get]
 func (h *URLHandler) RedirectURL(c echo.Context) error {
 	shortCode := c.Param("code")
-	fmt.Println(shortCode)
 
-	originalURL, err := h.urlService.GetURL(c.Request().Context(), shortCode)
+	// 短链接后加 + 时展示预览页
+	if code, ok := strings.CutSuffix(shortCode, "+"); ok {
+		return h.preview(c, code)
+	}
+
+	visitor := newVisitor(c)
+
+	url, err := h.urlService.GetURL(c.Request().Context(), c.Request().Host, shortCode, visitor)
+	if errors.Is(err, model.ErrURLNotActive) {
+		return renderPage(c, http.StatusForbidden, h.notActivePage, nil)
+	}
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		return redirectError(err)
+	}
+
+	if url.IsProtected() {
+		return renderPage(c, http.StatusOK, passwordPage, passwordPageData{})
+	}
+
+	// 从预览页点击继续访问时带有confirm参数
+	if url.AlwaysPreview && c.QueryParam("confirm") == "" {
+		return h.renderPreview(c, url)
+	}
+
+	return h.redirect(c, url, visitor)
+}
+
+// PreviewURL godoc
+// @Summary 预览短链接
+// @Description 展示短链接的目标地址、创建时间、过期时间和访问次数，不会重定向也不计入访问次数, 也可以在短链接后加 + 访问
+// @Tags URL
+// @Produce html
+// @Param code path string true "短链接代码"
+// @Success 200 {string} string "预览页"
+// @Failure 403 {string} string "尚未生效的页面"
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /{code}/preview [get]
+func (h *URLHandler) PreviewURL(c echo.Context) error {
+	return h.preview(c, c.Param("code"))
+}
+
+func (h *URLHandler) preview(c echo.Context, shortCode string) error {
+	url, err := h.urlService.GetURL(c.Request().Context(), c.Request().Host, shortCode, newVisitor(c))
+	if errors.Is(err, model.ErrURLNotActive) {
+		return renderPage(c, http.StatusForbidden, h.notActivePage, nil)
+	}
+	if err != nil {
+		return redirectError(err)
+	}
+
+	return h.renderPreview(c, url)
+}
+
+func (h *URLHandler) renderPreview(c echo.Context, url *model.URL) error {
+	preview, err := h.urlService.PreviewURL(c.Request().Context(), url)
+	if err != nil {
+		return redirectError(err)
+	}
+
+	return renderPage(c, http.StatusOK, previewPage, preview)
+}
+
+// UnlockURL godoc
+// @Summary 校验密码后重定向
+// @Description 提交受密码保护的短链接的密码，校验通过后重定向到原始URL
+// @Tags URL
+// @Accept x-www-form-urlencoded
+// @Produce html
+// @Param code path string true "短链接代码"
+// @Param password formData string true "访问密码"
+// @Success 303 {string} string "重定向到原始URL, 不论短链接设置的重定向方式都返回303"
+// @Failure 401 {string} string "密码错误"
+// @Failure 429 {string} string "尝试次数过多"
+// @Router /{code} [post]
+func (h *URLHandler) UnlockURL(c echo.Context) error {
+	shortCode := c.Param("code")
+	visitor := newVisitor(c)
+
+	url, err := h.urlService.UnlockURL(c.Request().Context(), c.Request().Host, shortCode, c.FormValue("password"), visitor)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrURLPasswordIncorrect):
+			return renderPage(c, http.StatusUnauthorized, passwordPage, passwordPageData{Error: err.Error()})
+		case errors.Is(err, model.ErrTooManyAttempts):
+			return renderPage(c, http.StatusTooManyRequests, passwordPage, passwordPageData{Error: err.Error()})
+		case errors.Is(err, model.ErrURLNotActive):
+			return renderPage(c, http.StatusForbidden, h.notActivePage, nil)
+		default:
+			return redirectError(err)
+		}
+	}
+
+	return h.redirect(c, url, visitor)
+}
+
+// newVisitor 从请求中提取访客信息
+func newVisitor(c echo.Context) model.Visitor {
+	req := c.Request()
+	visitor := model.Visitor{
+		IP:             c.RealIP(),
+		UserAgent:      req.UserAgent(),
+		Referrer:       req.Referer(),
+		AcceptLanguage: req.Header.Get("Accept-Language"),
+		Prefetch:       isPrefetch(req),
+		Time:           time.Now(),
+	}
+
+	if cookie, err := c.Cookie(variantCookie); err == nil {
+		visitor.StickyVariant, _ = strconv.ParseInt(cookie.Value, 10, 64)
+	}
+
+	return visitor
+}
+
+// redirect 记录点击并重定向到为访客选出的地址
+func (h *URLHandler) redirect(c echo.Context, url *model.URL, visitor model.Visitor) error {
+	// 旧数据中可能有javascript:等地址，只跳转到http和https
+	if !isHTTPURL(url.Destination) {
+		return echo.NewHTTPError(http.StatusNotFound, model.ErrURLNotFound.Error())
+	}
+
+	click := model.Click{
+		URLID:       url.ID,
+		Key:         url.Key(),
+		Destination: url.Destination,
+		VariantID:   url.VariantID,
+		Visitor:     visitor,
+	}
+
+	if err := h.urlService.ConsumeClick(c.Request().Context(), url, click); err != nil {
+		return redirectError(err)
+	}
+
+	// 回访的访客看到同一个版本
+	if url.VariantID != 0 && url.VariantID != visitor.StickyVariant {
+		c.SetCookie(&http.Cookie{
+			Name:     variantCookie,
+			Value:    strconv.FormatInt(url.VariantID, 10),
+			Path:     "/" + url.ShortCode,
+			MaxAge:   variantCookieMaxAge,
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
 	}
 
 	go func() {
-		if err := h.urlService.IncreViews(context.Background(), shortCode); err != nil {
-			log.Printf("failed to incre %s's view ", shortCode)
+		if err := h.urlService.RecordClick(context.Background(), click); err != nil {
+			log.Printf("failed to record %s's click: %v", click.Key, err)
 		}
 	}()
 
-	return c.Redirect(http.StatusPermanentRedirect, originalURL)
+	redirectType := url.RedirectType
+	if redirectType == "" {
+		redirectType = h.redirectType
+	}
+
+	if redirectType == model.RedirectMeta {
+		c.Response().Header().Set("Referrer-Policy", "no-referrer")
+	}
+
+	// 提交密码的POST请求总是返回303，浏览器改用GET访问目标地址，307和308会把密码一起转发给目标网站
+	if c.Request().Method == http.MethodPost {
+		return c.Redirect(http.StatusSeeOther, url.Destination)
+	}
+
+	// meta refresh不会携带Referer
+	if redirectType == model.RedirectMeta {
+		return renderPage(c, http.StatusOK, metaRedirectPage, url.Destination)
+	}
+
+	return c.Redirect(redirectStatus[redirectType], url.Destination)
+}
+
+func redirectError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrURLNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, model.ErrURLClickLimitReached):
+		return echo.NewHTTPError(http.StatusGone, err.Error())
+	case errors.Is(err, model.ErrBotNotAllowed):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
+
+// isHTTPURL 目标地址是否为http或https的URL
+func isHTTPURL(rawURL string) bool {
+	u, err := neturl.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	return (scheme == "http" || scheme == "https") && u.Host != ""
+}
+
+// isPrefetch 判断是否为HEAD请求或浏览器、聊天软件发起的预加载请求
+func isPrefetch(req *http.Request) bool {
+	if req.Method == http.MethodHead {
+		return true
+	}
+
+	for _, header := range []string{"Purpose", "Sec-Purpose", "X-Purpose", "X-Moz"} {
+		value := strings.ToLower(req.Header.Get(header))
+		if strings.Contains(value, "prefetch") || strings.Contains(value, "preview") {
+			return true
+		}
+	}
+
+	return false
 }
 
 // GetURLs godoc
 // @Summary 获取用户的所有短链接
-// @Description 分页获取当前用户创建的所有短链接
+// @Description 按游标分页获取当前用户创建的短链接，支持排序、关键词搜索和按标签、状态、创建时间、浏览量筛选，指定多个标签时只返回带有全部这些标签的短链接
 // @Tags URL
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer JWT token"
-// @Param page query int false "页码" default(1)
+// @Param cursor query string false "上一页返回的next_cursor"
 // @Param size query int false "每页数量" default(10)
+// @Param sort query string false "排序字段" Enums(created_at, expired_at, views, original_url) default(created_at)
+// @Param order query string false "排序方向" Enums(asc, desc) default(desc)
+// @Param with_total query bool false "是否返回总数"
+// @Param page query int false "页码，已废弃，请使用cursor"
+// @Param tag query []string false "标签名称，可以重复传入" collectionFormat(multi)
+// @Param q query string false "搜索原始URL、短链接代码、标题和备注"
+// @Param status query string false "状态" Enums(active, expired, scheduled, disabled)
+// @Param is_custom query bool false "是否为自定义代码"
+// @Param created_from query string false "创建时间起点(RFC3339)"
+// @Param created_to query string false "创建时间终点(RFC3339)，不包含"
+// @Param min_views query int false "最少浏览量"
+// @Param max_views query int false "最多浏览量"
 // @Success 200 {object} model.GetURLsResponse
+// @Failure 400 {object} echo.HTTPError
 // @Failure 500 {object} echo.HTTPError
 // @Router /api/urls [get]
 func (h *URLHandler) GetURLs(c echo.Context) error {
@@ -112,20 +404,23 @@ func (h *URLHandler) GetURLs(c echo.Context) error {
 
 	var req model.GetURLsRequest
 	if err := c.Bind(&req); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
-	}
-
-	if req.Page == 0 {
-		req.Page = 1
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
 	if req.Size == 0 {
 		req.Size = 10
 	}
 
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
 	req.UserID = userID
 
 	resp, err := h.urlService.GetURLs(c.Request().Context(), req)
+	if errors.Is(err, model.ErrInvalidFilter) || errors.Is(err, model.ErrInvalidCursor) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
@@ -135,46 +430,79 @@ func (h *URLHandler) GetURLs(c echo.Context) error {
 
 // DeleteURL godoc
 // @Summary 删除短链接
-// @Description 删除指定的短链接
+// @Description 将指定的短链接移入回收站，短链接立即停止跳转，在保留期限内可以恢复
 // @Tags URL
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer JWT token"
 // @Param code path string true "短链接代码"
+// @Param domain query string false "短链接所在的自定义域名，为空表示默认域名"
 // @Success 204 "No Content"
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
 // @Failure 500 {object} echo.HTTPError
 // @Router /api/url/{code} [delete]
 func (h *URLHandler) DeleteURL(c echo.Context) error {
-	shortCode := c.Param("code")
+	var req model.DeleteURLRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
 
-	if err := h.urlService.DeleteURL(c.Request().Context(), shortCode); err != nil {
+	if err := h.urlService.DeleteURL(c.Request().Context(), req); err != nil {
+		if errors.Is(err, model.ErrURLNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
 	return c.NoContent(http.StatusNoContent)
 }
 
-// UpdateURLDuration godoc
-// @Summary 更新短链接有效期
-// @Description 更新指定短链接的有效期
+// UpdateURL godoc
+// @Summary 修改短链接
+// @Description 修改短链接的目标地址、标题、备注、有效期和跳转设置，未传的字段保持不变。修改目标地址、标题或备注时会记录一个新的历史版本
 // @Tags URL
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Bearer JWT token"
 // @Param code path string true "短链接代码"
-// @Param request body model.UpdateURLDurationReq true "更新有效期请求"
+// @Param domain query string false "短链接所在的自定义域名，为空表示默认域名"
+// @Param request body model.UpdateURLRequest true "修改短链接请求"
 // @Success 204 "No Content"
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
 // @Failure 500 {object} echo.HTTPError
 // @Router /api/url/{code} [patch]
-func (h *URLHandler) UpdateURLDuration(c echo.Context) error {
-	var req model.UpdateURLDurationReq
+func (h *URLHandler) UpdateURL(c echo.Context) error {
+	var req model.UpdateURLRequest
 	if err := c.Bind(&req); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
 	req.Code = c.Param("code")
+	req.Domain = c.QueryParam("domain")
 
-	if err := h.urlService.UpdateURLDuration(c.Request().Context(), req); err != nil {
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	if err := h.urlService.UpdateURL(c.Request().Context(), req); err != nil {
+		if errors.Is(err, model.ErrURLNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, err.Error())
+		}
+		if errors.Is(err, model.ErrInvalidURL) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
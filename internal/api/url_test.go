@@ -0,0 +1,44 @@
+package api
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestIsHTTPURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		rawURL string
+		want   bool
+	}{
+		{name: "http", rawURL: "http://example.com/a?b=c", want: true},
+		{name: "https", rawURL: "https://example.com/", want: true},
+		{name: "upper case scheme", rawURL: "HTTPS://example.com/", want: true},
+		{name: "javascript", rawURL: "javascript:alert(1)", want: false},
+		{name: "data", rawURL: "data:text/html,<script>alert(1)</script>", want: false},
+		{name: "relative", rawURL: "/login", want: false},
+		{name: "protocol relative", rawURL: "//example.com/", want: false},
+		{name: "missing host", rawURL: "https:///path", want: false},
+		{name: "invalid", rawURL: "http://[::1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isHTTPURL(tt.rawURL); got != tt.want {
+				t.Errorf("isHTTPURL(%q) = %v, want %v", tt.rawURL, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetaRedirectPageEscapesDestination(t *testing.T) {
+	var buf bytes.Buffer
+	if err := metaRedirectPage.Execute(&buf, `https://example.com/?a="><script>alert(1)</script>`); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if strings.Contains(buf.String(), "<script>") {
+		t.Errorf("metaRedirectPage rendered an unescaped destination:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/labstack/echo/v4"
+)
+
+// GetVariants godoc
+// @Summary 获取短链接的A/B版本
+// @Description 返回短链接的所有A/B版本及其权重
+// @Tags A/B测试
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param code path string true "短链接代码"
+// @Param domain query string false "短链接所在的自定义域名，为空表示默认域名"
+// @Success 200 {object} model.GetVariantsResponse
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/url/{code}/variants [get]
+func (h *URLHandler) GetVariants(c echo.Context) error {
+	var req model.GetVariantsRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	resp, err := h.urlService.GetVariants(c.Request().Context(), req)
+	if err != nil {
+		return variantError(err)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CreateVariant godoc
+// @Summary 添加A/B版本
+// @Description 为短链接添加一个跳转版本，访客按权重比例分配到各个版本
+// @Tags A/B测试
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param code path string true "短链接代码"
+// @Param domain query string false "短链接所在的自定义域名，为空表示默认域名"
+// @Param request body model.CreateVariantRequest true "添加A/B版本请求"
+// @Success 201 {object} model.Variant
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/url/{code}/variants [post]
+func (h *URLHandler) CreateVariant(c echo.Context) error {
+	var req model.CreateVariantRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	req.Domain = c.QueryParam("domain")
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	rule, err := h.urlService.CreateVariant(c.Request().Context(), req)
+	if err != nil {
+		return variantError(err)
+	}
+
+	return c.JSON(http.StatusCreated, rule)
+}
+
+// UpdateVariant godoc
+// @Summary 修改A/B版本
+// @Description 修改A/B版本的跳转地址和权重
+// @Tags A/B测试
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param code path string true "短链接代码"
+// @Param domain query string false "短链接所在的自定义域名，为空表示默认域名"
+// @Param id path int true "版本ID"
+// @Param request body model.UpdateVariantRequest true "修改A/B版本请求"
+// @Success 200 {object} model.Variant
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/url/{code}/variants/{id} [put]
+func (h *URLHandler) UpdateVariant(c echo.Context) error {
+	var req model.UpdateVariantRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	req.Domain = c.QueryParam("domain")
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	rule, err := h.urlService.UpdateVariant(c.Request().Context(), req)
+	if err != nil {
+		return variantError(err)
+	}
+
+	return c.JSON(http.StatusOK, rule)
+}
+
+// DeleteVariant godoc
+// @Summary 删除A/B版本
+// @Description 删除短链接的一个A/B版本
+// @Tags A/B测试
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer JWT token"
+// @Param code path string true "短链接代码"
+// @Param domain query string false "短链接所在的自定义域名，为空表示默认域名"
+// @Param id path int true "版本ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} echo.HTTPError
+// @Failure 404 {object} echo.HTTPError
+// @Failure 500 {object} echo.HTTPError
+// @Router /api/url/{code}/variants/{id} [delete]
+func (h *URLHandler) DeleteVariant(c echo.Context) error {
+	var req model.DeleteVariantRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.Validate(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	userID, _ := c.Get("userID").(int)
+	req.UserID = userID
+
+	if err := h.urlService.DeleteVariant(c.Request().Context(), req); err != nil {
+		return variantError(err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func variantError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrURLNotFound), errors.Is(err, model.ErrVariantNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
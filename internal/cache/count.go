@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const urlCountPrefix = "urlcount:"
+
+// SetURLCount 缓存用户在某个筛选条件下的短链接总数，同一用户的所有筛选条件存在一个hash中
+func (r *RedisCache) SetURLCount(ctx context.Context, userID int, filter string, count int64) error {
+	key := urlCountPrefix + strconv.Itoa(userID)
+
+	if err := r.client.HSet(ctx, key, filter, count).Err(); err != nil {
+		return err
+	}
+
+	// 只在第一次写入时设置过期时间，避免不断写入新的筛选条件导致旧的总数一直不过期
+	ttl, err := r.client.TTL(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	if ttl < 0 {
+		return r.client.Expire(ctx, key, r.urlCountDuration).Err()
+	}
+
+	return nil
+}
+
+// GetURLCount 缓存未命中时ok为false
+func (r *RedisCache) GetURLCount(ctx context.Context, userID int, filter string) (count int64, ok bool, err error) {
+	count, err = r.client.HGet(ctx, urlCountPrefix+strconv.Itoa(userID), filter).Int64()
+	if err == redis.Nil {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return count, true, nil
+}
+
+// DelURLCount 用户的短链接发生变化时删除所有缓存的总数
+func (r *RedisCache) DelURLCount(ctx context.Context, userID int) error {
+	return r.client.Del(ctx, urlCountPrefix+strconv.Itoa(userID)).Err()
+}
@@ -0,0 +1,32 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const hostPrefix = "host:"
+
+// SetHost 缓存请求的Host解析后的域名，默认域名和未验证的域名解析为空
+func (r *RedisCache) SetHost(ctx context.Context, host, domain string) error {
+	return r.client.Set(ctx, hostPrefix+host, domain, r.urlDuration).Err()
+}
+
+// GetHost 缓存未命中时ok为false
+func (r *RedisCache) GetHost(ctx context.Context, host string) (domain string, ok bool, err error) {
+	domain, err = r.client.Get(ctx, hostPrefix+host).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return domain, true, nil
+}
+
+// DelHost 域名通过验证或被删除时删除解析结果
+func (r *RedisCache) DelHost(ctx context.Context, host string) error {
+	return r.client.Del(ctx, hostPrefix+host).Err()
+}
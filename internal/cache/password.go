@@ -0,0 +1,28 @@
+package cache
+
+import "context"
+
+const passwordAttemptPrefix = "pwd:"
+
+// IncrePasswordAttempts 增加该IP输错密码的次数，并返回累计次数
+// 过期时间只在第一次输错时设置，锁定窗口不会因为继续尝试而顺延
+func (r *RedisCache) IncrePasswordAttempts(ctx context.Context, shortCode, ip string) (int64, error) {
+	key := passwordAttemptPrefix + shortCode + ":" + ip
+
+	attempts, err := r.client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	if attempts == 1 {
+		if err := r.client.Expire(ctx, key, r.passwordAttemptDuration).Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	return attempts, nil
+}
+
+func (r *RedisCache) DelPasswordAttempts(ctx context.Context, shortCode, ip string) error {
+	return r.client.Del(ctx, passwordAttemptPrefix+shortCode+":"+ip).Err()
+}
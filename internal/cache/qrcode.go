@@ -0,0 +1,27 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const qrcodePrefix = "qr:"
+
+// SetQRCode 缓存生成的二维码，style区分不同的格式和样式
+func (r *RedisCache) SetQRCode(ctx context.Context, shortCode, style string, data []byte) error {
+	return r.client.Set(ctx, qrcodePrefix+shortCode+":"+style, data, r.qrcodeDuration).Err()
+}
+
+// GetQRCode 缓存未命中时返回nil
+func (r *RedisCache) GetQRCode(ctx context.Context, shortCode, style string) ([]byte, error) {
+	data, err := r.client.Get(ctx, qrcodePrefix+shortCode+":"+style).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/aeilang/urlshortener/config"
@@ -11,10 +12,20 @@ import (
 
 const urlPrefix = "url:"
 
+// 未配置时使用的默认过期时间，为0时EXPIRE会直接删除计数
+const (
+	defaultPasswordAttemptDuration = 15 * time.Minute
+	defaultURLCountDuration        = 5 * time.Minute
+)
+
 type RedisCache struct {
-	client            *redis.Client
-	urlDuration       time.Duration
-	emailCodeDuration time.Duration
+	client                  *redis.Client
+	urlDuration             time.Duration
+	emailCodeDuration       time.Duration
+	visitorDuration         time.Duration
+	passwordAttemptDuration time.Duration
+	qrcodeDuration          time.Duration
+	urlCountDuration        time.Duration
 }
 
 func NewRedisCache(cfg config.RedisConfig) (*RedisCache, error) {
@@ -28,31 +39,78 @@ func NewRedisCache(cfg config.RedisConfig) (*RedisCache, error) {
 		return nil, err
 	}
 
+	passwordAttemptDuration := cfg.PasswordAttemptDuration
+	if passwordAttemptDuration <= 0 {
+		passwordAttemptDuration = defaultPasswordAttemptDuration
+	}
+
+	urlCountDuration := cfg.URLCountDuration
+	if urlCountDuration <= 0 {
+		urlCountDuration = defaultURLCountDuration
+	}
+
 	return &RedisCache{
-		client:            client,
-		urlDuration:       cfg.UrlDuration,
-		emailCodeDuration: cfg.EmailCodeDuration,
+		client:                  client,
+		urlDuration:             cfg.UrlDuration,
+		emailCodeDuration:       cfg.EmailCodeDuration,
+		visitorDuration:         cfg.VisitorDuration,
+		passwordAttemptDuration: passwordAttemptDuration,
+		qrcodeDuration:          cfg.QRCodeDuration,
+		urlCountDuration:        urlCountDuration,
 	}, nil
 }
 
 func (c *RedisCache) SetURL(ctx context.Context, url model.URL) error {
-	if err := c.client.Set(ctx, urlPrefix+url.ShortCode, url.OriginalURL, c.urlDuration).Err(); err != nil {
+	data, err := json.Marshal(url)
+	if err != nil {
+		return err
+	}
+
+	if err := c.client.Set(ctx, urlPrefix+url.Key(), data, c.urlDuration).Err(); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (c *RedisCache) GetURL(ctx context.Context, shortCode string) (originalURL string, err error) {
-	originalURL = c.client.Get(ctx, urlPrefix+shortCode).Val()
+// GetURL 缓存未命中时返回nil
+func (c *RedisCache) GetURL(ctx context.Context, shortCode string) (*model.URL, error) {
+	data, err := c.client.Get(ctx, urlPrefix+shortCode).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var url model.URL
+	// 旧版本缓存的是原始URL字符串，按未命中处理
+	if err := json.Unmarshal(data, &url); err != nil {
+		return nil, nil
+	}
 
-	return originalURL, nil
+	return &url, nil
 }
 
 func (c *RedisCache) DelURL(ctx context.Context, shortCode string) error {
 	return c.client.Del(ctx, urlPrefix+shortCode).Err()
 }
 
+// DelURLs 通过pipeline一次删除多个短链接的缓存
+func (c *RedisCache) DelURLs(ctx context.Context, shortCodes []string) error {
+	if len(shortCodes) == 0 {
+		return nil
+	}
+
+	pipe := c.client.Pipeline()
+	for _, shortCode := range shortCodes {
+		pipe.Del(ctx, urlPrefix+shortCode)
+	}
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
 func (c *RedisCache) Close() error {
 	return c.client.Close()
 }
@@ -2,6 +2,8 @@ package cache
 
 import (
 	"context"
+	"strconv"
+	"strings"
 
 	"github.com/go-redis/redis/v8"
 )
@@ -12,8 +14,18 @@ func (r *RedisCache) IncreViews(ctx context.Context, shortCode string) error {
 	return r.client.Incr(context.Background(), viewPrifix+shortCode).Err()
 }
 
+// ScanViews 遍历所有尚未同步的浏览量，返回去掉前缀后的短链接标识
 func (r *RedisCache) ScanViews(ctx context.Context, cursor uint64, batchSize int64) (keys []string, nextCursor uint64, err error) {
-	return r.client.Scan(ctx, cursor, viewPrifix, batchSize).Result()
+	keys, nextCursor, err = r.client.Scan(ctx, cursor, viewPrifix+"*", batchSize).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, viewPrifix)
+	}
+
+	return keys, nextCursor, nil
 }
 
 func (r *RedisCache) GetViews(ctx context.Context, shortCode string) (int, error) {
@@ -29,6 +41,34 @@ func (r *RedisCache) GetViews(ctx context.Context, shortCode string) (int, error
 	return views, nil
 }
 
-func (r *RedisCache) DelViews(ctx context.Context, key string) error {
-	return r.client.Del(ctx, key).Err()
+func (r *RedisCache) DelViews(ctx context.Context, shortCode string) error {
+	return r.client.Del(ctx, viewPrifix+shortCode).Err()
+}
+
+// GetViewsMulti 一次查询多个短链接尚未同步的浏览量，结果与shortCodes一一对应
+func (r *RedisCache) GetViewsMulti(ctx context.Context, shortCodes []string) ([]int, error) {
+	if len(shortCodes) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(shortCodes))
+	for i, code := range shortCodes {
+		keys[i] = viewPrifix + code
+	}
+
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]int, len(values))
+	for i, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		views[i], _ = strconv.Atoi(s)
+	}
+
+	return views, nil
 }
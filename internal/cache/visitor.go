@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const visitorPrefix = "uv:"
+
+// AddVisitor 将访客指纹同时记录到链接总的和当天(UTC)的HyperLogLog中
+func (r *RedisCache) AddVisitor(ctx context.Context, shortCode, fingerprint string, at time.Time) error {
+	dayKey := visitorDayKey(shortCode, at)
+
+	pipe := r.client.Pipeline()
+	pipe.PFAdd(ctx, visitorPrefix+shortCode, fingerprint)
+	pipe.PFAdd(ctx, dayKey, fingerprint)
+	if r.visitorDuration > 0 {
+		pipe.Expire(ctx, dayKey, r.visitorDuration)
+	}
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+// CountVisitors 返回链接的独立访客数
+func (r *RedisCache) CountVisitors(ctx context.Context, shortCode string) (int64, error) {
+	return r.client.PFCount(ctx, visitorPrefix+shortCode).Result()
+}
+
+// CountVisitorsMulti 通过pipeline一次查询多个链接的独立访客数，结果与shortCodes一一对应
+func (r *RedisCache) CountVisitorsMulti(ctx context.Context, shortCodes []string) ([]int64, error) {
+	if len(shortCodes) == 0 {
+		return nil, nil
+	}
+
+	pipe := r.client.Pipeline()
+	cmds := make([]*redis.IntCmd, len(shortCodes))
+	for i, code := range shortCodes {
+		cmds[i] = pipe.PFCount(ctx, visitorPrefix+code)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	visitors := make([]int64, len(cmds))
+	for i, cmd := range cmds {
+		visitors[i] = cmd.Val()
+	}
+
+	return visitors, nil
+}
+
+// CountVisitorsBetween 合并时间范围内每天的HyperLogLog, 返回去重后的访客数
+func (r *RedisCache) CountVisitorsBetween(ctx context.Context, shortCode string, from, to time.Time) (int64, error) {
+	if r.visitorDuration > 0 {
+		if earliest := to.Add(-r.visitorDuration); from.Before(earliest) {
+			from = earliest
+		}
+	}
+
+	var keys []string
+	for day := from.UTC().Truncate(24 * time.Hour); day.Before(to); day = day.Add(24 * time.Hour) {
+		keys = append(keys, visitorDayKey(shortCode, day))
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	return r.client.PFCount(ctx, keys...).Result()
+}
+
+// DelVisitors 删除链接总的HyperLogLog, 每天的HyperLogLog会自动过期
+func (r *RedisCache) DelVisitors(ctx context.Context, shortCode string) error {
+	return r.client.Del(ctx, visitorPrefix+shortCode).Err()
+}
+
+func visitorDayKey(shortCode string, at time.Time) string {
+	return visitorPrefix + shortCode + ":" + at.UTC().Format("20060102")
+}
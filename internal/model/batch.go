@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// 批量操作的类型
+const (
+	BatchDelete    = "delete"     // 移入回收站
+	BatchExtend    = "extend"     // 延长有效期，已过期的从当前时间开始计算
+	BatchSetExpiry = "set_expiry" // 设置过期时间
+	BatchAddTag    = "add_tag"
+	BatchRemoveTag = "remove_tag"
+	BatchDisable   = "disable" // 停用后短链接无法跳转
+	BatchEnable    = "enable"
+)
+
+// 批量操作中每个短链接的结果
+const (
+	BatchOK       = "ok"
+	BatchNotFound = "not_found" // 短链接不存在或不属于当前用户
+)
+
+type BatchURLsRequest struct {
+	Codes     []string   `json:"codes" validate:"required,min=1,max=1000,dive,min=4,max=10,alphanum"`
+	Action    string     `json:"action" validate:"required,oneof=delete extend set_expiry add_tag remove_tag disable enable"`
+	Duration  int        `json:"duration,omitempty" validate:"omitempty,min=1,max=100"` // extend时延长的小时数
+	ExpiredAt *time.Time `json:"expired_at,omitempty" validate:"omitempty,after"`       // set_expiry时的过期时间
+	TagID     int64      `json:"tag_id,omitempty"`                                      // add_tag和remove_tag时的标签ID
+	Domain    string     `json:"domain,omitempty" validate:"omitempty,fqdn,max=253"`    // 短链接所在的自定义域名，为空表示默认域名
+	UserID    int        `json:"-"`
+}
+
+type BatchURLResult struct {
+	Code   string `json:"code"`
+	Status string `json:"status"`
+}
+
+type BatchURLsResponse struct {
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Items     []BatchURLResult `json:"items"`
+}
@@ -0,0 +1,40 @@
+package model
+
+// 批量创建短链接时每一行的结果
+const (
+	BulkCreated    = "created"     // 创建成功
+	BulkCodeTaken  = "code_taken"  // 自定义代码已被使用
+	BulkInvalidURL = "invalid_url" // 原始URL不正确
+	BulkInvalid    = "invalid"     // 其他字段不正确
+	BulkFailed     = "failed"      // 数据库错误等原因创建失败
+)
+
+// BulkURLItem 批量创建中的一行，CSV的表头与JSON的字段名相同，多个标签用分号分隔
+type BulkURLItem struct {
+	Row         int      `json:"-"` // 从1开始的行号，CSV不包括表头
+	OriginalURL string   `json:"original_url" validate:"required,http_url"`
+	CustomCode  string   `json:"custom_code,omitempty" validate:"omitempty,min=4,max=10,alphanum"`
+	Duration    *int     `json:"duration,omitempty" validate:"omitempty,min=1,max=100"`
+	Title       string   `json:"title,omitempty" validate:"omitempty,max=255"`
+	Notes       string   `json:"notes,omitempty" validate:"omitempty,max=2000"`
+	Tags        []string `json:"tags,omitempty" validate:"max=10,dive,required,max=50"`
+}
+
+type BulkCreateURLsRequest struct {
+	Domain string        `query:"domain" validate:"omitempty,fqdn,max=253"` // 所有短链接使用的已验证自定义域名
+	Items  []BulkURLItem `json:"-"`
+	UserID int           `json:"-"`
+}
+
+type BulkURLResult struct {
+	Row      int    `json:"row"`
+	Status   string `json:"status"`
+	ShortURL string `json:"short_url,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type BulkCreateURLsResponse struct {
+	Created int             `json:"created"`
+	Failed  int             `json:"failed"`
+	Items   []BulkURLResult `json:"items"`
+}
@@ -0,0 +1,10 @@
+package model
+
+// Click 一次短链接访问的记录
+type Click struct {
+	URLID       int64
+	Key         string // 短链接在redis中的标识，见 URLKey
+	Destination string
+	VariantID   int64
+	Visitor
+}
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// Domain 用户的自定义短链接域名，需要通过DNS TXT记录验证所有权后才能使用
+type Domain struct {
+	ID          int64      `json:"id"`
+	Host        string     `json:"host"`
+	Verified    bool       `json:"verified"`
+	VerifiedAt  *time.Time `json:"verified_at,omitempty"`
+	RecordName  string     `json:"record_name"`  // 需要添加的TXT记录名称
+	RecordValue string     `json:"record_value"` // 需要添加的TXT记录值
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type CreateDomainRequest struct {
+	Host   string `json:"host" validate:"required,fqdn,max=253"`
+	UserID int    `json:"-"`
+}
+
+type GetDomainsResponse struct {
+	Items []Domain `json:"items"`
+}
+
+type VerifyDomainRequest struct {
+	ID     int64 `param:"id" validate:"required"`
+	UserID int   `json:"-"`
+}
+
+type DeleteDomainRequest struct {
+	ID     int64 `param:"id" validate:"required"`
+	UserID int   `json:"-"`
+}
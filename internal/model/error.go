@@ -5,3 +5,29 @@ import "errors"
 var ErrUserNameOrPasswordFailed = errors.New("用户名或密码错误")
 var ErrEmailAleadyExist = errors.New("邮箱已存在")
 var ErrEmailCodeNotEqual = errors.New("邮箱验证码不正确")
+var ErrURLNotFound = errors.New("短链接不存在")
+var ErrInvalidTimezone = errors.New("时区不正确")
+var ErrInvalidStatsRange = errors.New("统计时间范围不正确")
+var ErrURLPasswordIncorrect = errors.New("密码不正确")
+var ErrTooManyAttempts = errors.New("尝试次数过多，请稍后再试")
+var ErrURLClickLimitReached = errors.New("短链接的访问次数已用完")
+var ErrBotNotAllowed = errors.New("该短链接不允许机器人访问")
+var ErrURLNotActive = errors.New("短链接尚未生效")
+var ErrRuleNotFound = errors.New("定向规则不存在")
+var ErrInvalidRule = errors.New("定向规则的条件不正确")
+var ErrVariantNotFound = errors.New("A/B版本不存在")
+var ErrInvalidQRCode = errors.New("二维码参数不正确")
+var ErrRevisionNotFound = errors.New("历史版本不存在")
+var ErrDomainNotFound = errors.New("域名不存在")
+var ErrDomainNotVerified = errors.New("域名尚未通过验证")
+var ErrDomainVerifyFailed = errors.New("未找到域名的验证记录")
+var ErrDomainTaken = errors.New("域名已被其他用户验证")
+var ErrDomainInUse = errors.New("域名下还有短链接，无法删除")
+var ErrInvalidDomain = errors.New("不能使用该域名")
+var ErrTagNotFound = errors.New("标签不存在")
+var ErrTagExists = errors.New("标签已存在")
+var ErrInvalidTag = errors.New("标签名称不能为空")
+var ErrInvalidFilter = errors.New("筛选条件不正确")
+var ErrInvalidCursor = errors.New("分页游标不正确")
+var ErrInvalidBatch = errors.New("批量操作缺少参数")
+var ErrInvalidURL = errors.New("原始URL不正确")
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// 导出格式
+const (
+	ExportCSV    = "csv"
+	ExportJSON   = "json"
+	ExportNDJSON = "ndjson" // 每行一个JSON对象
+)
+
+type ExportURLsRequest struct {
+	Format string `query:"format" validate:"omitempty,oneof=csv json ndjson"`
+	UserID int    `query:"-"`
+}
+
+// ExportedURL 导出的一条短链接，浏览量包括尚未同步到数据库的部分
+type ExportedURL struct {
+	ShortURL    string    `json:"short_url"`
+	OriginalURL string    `json:"original_url"`
+	ShortCode   string    `json:"short_code"`
+	Domain      string    `json:"domain,omitempty"`
+	Title       string    `json:"title"`
+	Notes       string    `json:"notes"`
+	Tags        []string  `json:"tags"`
+	IsCustom    bool      `json:"is_custom"`
+	Views       uint      `json:"views"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiredAt   time.Time `json:"expired_at"`
+}
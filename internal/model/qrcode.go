@@ -0,0 +1,20 @@
+package model
+
+type GetQRCodeRequest struct {
+	Code       string `param:"code" validate:"required,min=4,max=10,alphanum"`
+	Domain     string `query:"domain" json:"-" validate:"omitempty,fqdn,max=253"` // 短链接所在的自定义域名，为空表示默认域名
+	Format     string `query:"format" validate:"required,oneof=png svg"`
+	Size       int    `query:"size" validate:"min=64,max=2048"`
+	Margin     *int   `query:"margin" validate:"omitempty,min=0,max=16"`
+	Level      string `query:"level" validate:"required,oneof=L M Q H"`
+	Foreground string `query:"fg" validate:"required,len=6,hexadecimal"`
+	Background string `query:"bg" validate:"required,len=6,hexadecimal"`
+	Logo       bool   `query:"logo"`
+	UserID     int    `query:"-"`
+}
+
+// QRCode 生成的二维码图片
+type QRCode struct {
+	ContentType string
+	Data        []byte
+}
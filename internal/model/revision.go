@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// Revision 短链接的一个历史版本，记录修改后的内容
+type Revision struct {
+	ID           int64     `json:"id"`
+	OriginalURL  string    `json:"original_url"`
+	Title        string    `json:"title"`
+	Notes        string    `json:"notes"`
+	UserID       int       `json:"user_id"`
+	RestoredFrom *int64    `json:"restored_from,omitempty"` // 由回滚产生时为回滚到的版本
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type GetHistoryRequest struct {
+	Code   string `param:"code" validate:"required,min=4,max=10,alphanum"`
+	Domain string `query:"domain" json:"-" validate:"omitempty,fqdn,max=253"` // 短链接所在的自定义域名，为空表示默认域名
+	UserID int    `json:"-"`
+}
+
+type GetHistoryResponse struct {
+	Items []Revision `json:"items"`
+}
+
+type RollbackURLRequest struct {
+	Code   string `param:"code" validate:"required,min=4,max=10,alphanum"`
+	Domain string `query:"domain" json:"-" validate:"omitempty,fqdn,max=253"` // 短链接所在的自定义域名，为空表示默认域名
+	ID     int64  `param:"id" validate:"required"`
+	UserID int    `json:"-"`
+}
@@ -0,0 +1,74 @@
+package model
+
+import "time"
+
+// 定向规则的条件类型
+const (
+	RuleCountry  = "country"  // 国家代码，例如 US,CA
+	RuleOS       = "os"       // 操作系统，例如 iOS,Android
+	RuleDevice   = "device"   // 设备类型，例如 mobile,tablet
+	RuleLanguage = "language" // Accept-Language中最优先的语言，例如 zh,en-US
+	RuleWeekday  = "weekday"  // 星期，例如 sat,sun
+	RuleTime     = "time"     // 一天中的时间段，例如 09:00-18:00，支持跨零点
+)
+
+// Rule 短链接的定向规则，按Position从小到大匹配，命中后跳转到Destination
+type Rule struct {
+	ID          int64  `json:"id"`
+	Position    int    `json:"position"`
+	Type        string `json:"type"`
+	Value       string `json:"value"`
+	Timezone    string `json:"timezone,omitempty"`
+	Destination string `json:"destination"`
+}
+
+type CreateRuleRequest struct {
+	Code        string `param:"code" validate:"required,min=4,max=10,alphanum"`
+	Domain      string `query:"domain" json:"-" validate:"omitempty,fqdn,max=253"` // 短链接所在的自定义域名，为空表示默认域名
+	Position    int    `json:"position" validate:"min=0"`
+	Type        string `json:"type" validate:"required,oneof=country os device language weekday time"`
+	Value       string `json:"value" validate:"required,max=256"`
+	Timezone    string `json:"timezone,omitempty"`
+	Destination string `json:"destination" validate:"required,http_url"`
+	UserID      int    `json:"-"`
+}
+
+type UpdateRuleRequest struct {
+	Code        string `param:"code" validate:"required,min=4,max=10,alphanum"`
+	Domain      string `query:"domain" json:"-" validate:"omitempty,fqdn,max=253"` // 短链接所在的自定义域名，为空表示默认域名
+	ID          int64  `param:"id" validate:"required"`
+	Position    int    `json:"position" validate:"min=0"`
+	Type        string `json:"type" validate:"required,oneof=country os device language weekday time"`
+	Value       string `json:"value" validate:"required,max=256"`
+	Timezone    string `json:"timezone,omitempty"`
+	Destination string `json:"destination" validate:"required,http_url"`
+	UserID      int    `json:"-"`
+}
+
+type DeleteRuleRequest struct {
+	Code   string `param:"code" validate:"required,min=4,max=10,alphanum"`
+	Domain string `query:"domain" json:"-" validate:"omitempty,fqdn,max=253"` // 短链接所在的自定义域名，为空表示默认域名
+	ID     int64  `param:"id" validate:"required"`
+	UserID int    `json:"-"`
+}
+
+type GetRulesRequest struct {
+	Code   string `param:"code" validate:"required,min=4,max=10,alphanum"`
+	Domain string `query:"domain" json:"-" validate:"omitempty,fqdn,max=253"` // 短链接所在的自定义域名，为空表示默认域名
+	UserID int    `json:"-"`
+}
+
+type GetRulesResponse struct {
+	Items []Rule `json:"items"`
+}
+
+// Visitor 发起重定向请求的访客信息，用于匹配定向规则和记录点击
+type Visitor struct {
+	IP             string
+	UserAgent      string
+	Referrer       string
+	AcceptLanguage string
+	Prefetch       bool  // HEAD请求或浏览器的预加载请求，按机器人处理
+	StickyVariant  int64 // cookie中记录的上次分配的A/B版本
+	Time           time.Time
+}
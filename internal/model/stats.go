@@ -0,0 +1,81 @@
+package model
+
+import "time"
+
+type GetStatsRequest struct {
+	Code     string    `param:"code" validate:"required"`
+	Domain   string    `query:"domain" json:"-" validate:"omitempty,fqdn,max=253"` // 短链接所在的自定义域名，为空表示默认域名
+	Interval string    `query:"interval" validate:"required,oneof=hour day week"`
+	From     time.Time `query:"from"`
+	To       time.Time `query:"to"`
+	Timezone string    `query:"tz"`
+	UserID   int       `query:"-"`
+}
+
+type StatsBucket struct {
+	Time      time.Time `json:"time"`
+	Clicks    int64     `json:"clicks"`
+	BotClicks int64     `json:"bot_clicks"`
+}
+
+type GetStatsResponse struct {
+	Code           string         `json:"code"`
+	Interval       string         `json:"interval"`
+	Timezone       string         `json:"timezone"`
+	From           time.Time      `json:"from"`
+	To             time.Time      `json:"to"`
+	Total          int64          `json:"total"`
+	BotClicks      int64          `json:"bot_clicks"`
+	UniqueVisitors int64          `json:"unique_visitors"`
+	Buckets        []StatsBucket  `json:"buckets"`
+	Variants       []VariantStats `json:"variants,omitempty"`
+}
+
+type GetBreakdownRequest struct {
+	Code   string    `param:"code" validate:"required"`
+	Domain string    `query:"domain" json:"-" validate:"omitempty,fqdn,max=253"` // 短链接所在的自定义域名，为空表示默认域名
+	From   time.Time `query:"from"`
+	To     time.Time `query:"to"`
+	UserID int       `query:"-"`
+}
+
+type CountryStats struct {
+	Country string `json:"country"`
+	Clicks  int64  `json:"clicks"`
+}
+
+type CityStats struct {
+	Country string `json:"country"`
+	City    string `json:"city"`
+	Clicks  int64  `json:"clicks"`
+}
+
+type GetGeoStatsResponse struct {
+	Code      string         `json:"code"`
+	From      time.Time      `json:"from"`
+	To        time.Time      `json:"to"`
+	Countries []CountryStats `json:"countries"`
+	Cities    []CityStats    `json:"cities"`
+}
+
+type GetUserBreakdownRequest struct {
+	From   time.Time `query:"from"`
+	To     time.Time `query:"to"`
+	UserID int       `query:"-"`
+}
+
+type BreakdownItem struct {
+	Name   string `json:"name"`
+	Clicks int64  `json:"clicks"`
+}
+
+type GetDeviceStatsResponse struct {
+	Code     string          `json:"code,omitempty"`
+	From     time.Time       `json:"from"`
+	To       time.Time       `json:"to"`
+	Devices  []BreakdownItem `json:"devices"`
+	OS       []BreakdownItem `json:"os"`
+	Browsers []BreakdownItem `json:"browsers"`
+	Humans   int64           `json:"humans"`
+	Bots     int64           `json:"bots"`
+}
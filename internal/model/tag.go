@@ -0,0 +1,35 @@
+package model
+
+// Tag 用户用来分类管理短链接的标签，名称在同一用户下唯一，比较时忽略大小写
+type Tag struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type GetTagsResponse struct {
+	Items []Tag `json:"items"`
+}
+
+type CreateTagRequest struct {
+	Name   string `json:"name" validate:"required,max=50"`
+	UserID int    `json:"-"`
+}
+
+type RenameTagRequest struct {
+	ID     int64  `param:"id" validate:"required"`
+	Name   string `json:"name" validate:"required,max=50"`
+	UserID int    `json:"-"`
+}
+
+type DeleteTagRequest struct {
+	ID     int64 `param:"id" validate:"required"`
+	UserID int   `json:"-"`
+}
+
+// URLTagRequest 为短链接添加或移除标签
+type URLTagRequest struct {
+	Code   string `param:"code" validate:"required,min=4,max=10,alphanum"`
+	Domain string `query:"domain" json:"-" validate:"omitempty,fqdn,max=253"` // 短链接所在的自定义域名，为空表示默认域名
+	ID     int64  `param:"id" validate:"required"`
+	UserID int    `json:"-"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// TrashedURL 回收站中的短链接，超过保留期限后会被永久删除
+type TrashedURL struct {
+	ID          int64     `json:"id"`
+	OriginalURL string    `json:"original_url"`
+	ShortURL    string    `json:"short_url"`
+	Title       string    `json:"title"`
+	DeletedAt   time.Time `json:"deleted_at"`
+	PurgeAt     time.Time `json:"purge_at"`
+}
+
+type GetTrashRequest struct {
+	Page   uint `query:"page"`
+	Size   uint `query:"size"`
+	UserID int  `query:"-"`
+}
+
+type GetTrashResponse struct {
+	Items []TrashedURL `json:"items"`
+	Total int          `json:"total"`
+}
+
+type RestoreURLRequest struct {
+	Code   string `param:"code" validate:"required,min=4,max=10,alphanum"`
+	Domain string `query:"domain" json:"-" validate:"omitempty,fqdn,max=253"` // 短链接所在的自定义域名，为空表示默认域名
+	UserID int    `json:"-"`
+}
+
+type PurgeURLRequest struct {
+	Code   string `param:"code" validate:"required,min=4,max=10,alphanum"`
+	Domain string `query:"domain" json:"-" validate:"omitempty,fqdn,max=253"` // 短链接所在的自定义域名，为空表示默认域名
+	UserID int    `json:"-"`
+}
@@ -2,47 +2,180 @@ package model
 
 import "time"
 
+// 短链接的重定向方式
+const (
+	RedirectMovedPermanently = "301"
+	RedirectFound            = "302"
+	RedirectTemporary        = "307"
+	RedirectPermanent        = "308"
+	RedirectMeta             = "meta" // 返回通过meta refresh和JS跳转的页面，不携带Referer
+)
+
 type CreateURLRequest struct {
-	OriginalURL string `json:"original_url" validate:"required,url"`
-	CustomCode  string `json:"custom_code,omitempty" validate:"omitempty,min=4,max=10,alphanum"`
-	Duration    *int   `json:"duration,omitempty" validate:"omitempty,min=1,max=100"`
-	UserID      int    `json:"-"`
+	OriginalURL   string     `json:"original_url" validate:"required,http_url"`
+	CustomCode    string     `json:"custom_code,omitempty" validate:"omitempty,min=4,max=10,alphanum"`
+	Duration      *int       `json:"duration,omitempty" validate:"omitempty,min=1,max=100"`
+	Password      string     `json:"password,omitempty" validate:"omitempty,min=4,max=64"`
+	MaxClicks     *int       `json:"max_clicks,omitempty" validate:"omitempty,min=1,max=2147483647"`
+	ActiveFrom    *time.Time `json:"active_from,omitempty"`
+	RedirectType  string     `json:"redirect_type,omitempty" validate:"omitempty,oneof=301 302 307 308 meta"`
+	AlwaysPreview bool       `json:"always_preview,omitempty"`
+	Title         string     `json:"title,omitempty" validate:"omitempty,max=255"`
+	Notes         string     `json:"notes,omitempty" validate:"omitempty,max=2000"`
+	Domain        string     `json:"domain,omitempty" validate:"omitempty,fqdn,max=253"` // 已验证的自定义域名，为空时使用默认域名
+	ForceNew      bool       `json:"force_new,omitempty"`                                // 总是创建新的短链接，不复用同一目标地址的已有短链接
+	UserID        int        `json:"-"`
 }
 
 type CreateURLResponse struct {
 	ShortURL string `json:"short_url"`
+	Existing bool   `json:"existing"`          // 是否为已有的短链接
+	Warning  string `json:"warning,omitempty"` // 目标地址可能有风险时的提示
 }
 
+// 目标地址的风险提示
+const (
+	WarningMixedScript = "mixed_script_host" // 域名混用了多种文字，可能是仿冒的域名
+)
+
+// URL的状态，用于筛选短链接
+const (
+	URLStatusActive    = "active"    // 可以正常跳转
+	URLStatusExpired   = "expired"   // 已过期或访问次数已用完
+	URLStatusScheduled = "scheduled" // 尚未到生效时间
+	URLStatusDisabled  = "disabled"  // 已被用户停用
+)
+
+// 短链接列表的排序字段
+const (
+	URLSortCreatedAt   = "created_at"
+	URLSortExpiredAt   = "expired_at"
+	URLSortViews       = "views"
+	URLSortOriginalURL = "original_url"
+)
+
 type GetURLsRequest struct {
-	Page   uint `query:"page"`
-	Size   uint `query:"size"`
-	UserID int  `query:"-"`
+	Cursor      string    `query:"cursor" validate:"max=1024"` // 上一页返回的next_cursor，为空表示第一页
+	Page        uint      `query:"page"`                       // 已废弃，按页码翻页，指定了cursor时忽略
+	Size        uint      `query:"size" validate:"max=100"`
+	Sort        string    `query:"sort" validate:"omitempty,oneof=created_at expired_at views original_url"`
+	Order       string    `query:"order" validate:"omitempty,oneof=asc desc"`
+	WithTotal   bool      `query:"with_total"`                        // 是否返回符合条件的总数
+	Tags        []string  `query:"tag" validate:"max=10,dive,max=50"` // 只返回带有全部这些标签的短链接
+	Q           string    `query:"q" validate:"max=200"`              // 搜索原始URL、短链接代码、标题和备注
+	Status      string    `query:"status" validate:"omitempty,oneof=active expired scheduled disabled"`
+	IsCustom    *bool     `query:"is_custom"`
+	CreatedFrom time.Time `query:"created_from"`
+	CreatedTo   time.Time `query:"created_to"`
+	MinViews    *uint     `query:"min_views"` // 浏览量不包括尚未同步到数据库的部分
+	MaxViews    *uint     `query:"max_views"`
+	UserID      int       `query:"-"`
 }
 
 type FullURL struct {
-	ID          int       `json:"id"`
-	OriginalURL string    `json:"original_url"`
-	ShortURL    string    `json:"short_url"`
-	ExpiredAt   time.Time `json:"expired_at"`
-	IsCustom    bool      `json:"is_custom"`
-	Views       uint      `json:"views"`
+	ID              int       `json:"id"`
+	OriginalURL     string    `json:"original_url"` // 规范化后的URL，实际跳转的地址
+	RawURL          string    `json:"raw_url"`      // 用户输入的原始URL
+	Warning         string    `json:"warning,omitempty"`
+	ShortURL        string    `json:"short_url"`
+	Title           string    `json:"title"`
+	Notes           string    `json:"notes"`
+	ExpiredAt       time.Time `json:"expired_at"`
+	IsCustom        bool      `json:"is_custom"`
+	Views           uint      `json:"views"`
+	UniqueVisitors  uint      `json:"unique_visitors"`
+	MaxClicks       *int      `json:"max_clicks,omitempty"`
+	RemainingClicks *int      `json:"remaining_clicks,omitempty"`
+	Disabled        bool      `json:"disabled"`
+	Tags            []Tag     `json:"tags"`
 }
 
 type GetURLsResponse struct {
-	Items []FullURL `json:"items"`
-	Total int       `json:"total"`
+	Items      []FullURL `json:"items"`
+	NextCursor string    `json:"next_cursor,omitempty"` // 为空表示没有下一页
+	Total      *int      `json:"total,omitempty"`
 }
 
+// URL 重定向时需要的短链接信息，会以JSON的形式缓存在redis中
 type URL struct {
-	OriginalURL string
+	ID            int64      `json:"id"`
+	OriginalURL   string     `json:"original_url"`
+	ShortCode     string     `json:"short_code"`
+	Domain        string     `json:"domain,omitempty"` // 自定义域名，为空表示默认域名
+	PasswordHash  string     `json:"password_hash,omitempty"`
+	MaxClicks     int        `json:"max_clicks,omitempty"`
+	ActiveFrom    *time.Time `json:"active_from,omitempty"`
+	Rules         []Rule     `json:"rules,omitempty"`
+	Variants      []Variant  `json:"variants,omitempty"`
+	RedirectType  string     `json:"redirect_type,omitempty"`
+	AlwaysPreview bool       `json:"always_preview,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	ExpiredAt     time.Time  `json:"expired_at"`
+	Destination   string     `json:"-"` // 按定向规则或A/B版本为本次访问选出的跳转地址
+	VariantID     int64      `json:"-"` // 本次访问分配到的A/B版本，未分配时为0
+}
+
+// URLKey 短链接在redis中的标识，不同域名下可以有相同的短链接代码
+func URLKey(domain, shortCode string) string {
+	if domain == "" {
+		return shortCode
+	}
+	return domain + "/" + shortCode
+}
+
+func (u *URL) Key() string {
+	return URLKey(u.Domain, u.ShortCode)
+}
+
+func (u *URL) IsProtected() bool {
+	return u.PasswordHash != ""
+}
+
+// IsActive 是否已到生效时间
+func (u *URL) IsActive(now time.Time) bool {
+	return u.ActiveFrom == nil || !u.ActiveFrom.After(now)
+}
+
+// IsLimited 是否限制了重定向次数，MaxClicks为1时即为一次性链接
+func (u *URL) IsLimited() bool {
+	return u.MaxClicks > 0
+}
+
+// IsExpired 缓存的有效期可能比短链接更长，需要再次检查过期时间
+func (u *URL) IsExpired(now time.Time) bool {
+	return !u.ExpiredAt.IsZero() && !u.ExpiredAt.After(now)
+}
+
+// URLPreview 预览页展示的短链接信息
+// 受密码保护或限制访问次数的短链接不展示目标地址，避免绕过密码和次数限制
+type URLPreview struct {
 	ShortCode   string
+	Destination string
+	CreatedAt   time.Time
+	ExpiredAt   time.Time
+	Views       int
+	Protected   bool
+	Limited     bool
 }
 
+// DeleteURLRequest 将短链接移入回收站
 type DeleteURLRequest struct {
-	Code string `param:"code" validate:"required,len=6,alphanum"`
+	Code   string `param:"code" validate:"required,min=4,max=10,alphanum"`
+	Domain string `query:"domain" json:"-" validate:"omitempty,fqdn,max=253"` // 短链接所在的自定义域名，为空表示默认域名
+	UserID int    `json:"-"`
 }
 
-type UpdateURLDurationReq struct {
-	Code      string    `param:"code" validate:"required,len=6,alphanum"`
-	ExpiredAt time.Time `json:"expired_at" validate:"required,after"`
+// UpdateURLRequest 修改短链接，未传的字段保持不变，修改目标地址、标题或备注时会记录历史版本
+type UpdateURLRequest struct {
+	Code            string     `param:"code" validate:"required,min=4,max=10,alphanum"`
+	Domain          string     `query:"domain" json:"-" validate:"omitempty,fqdn,max=253"` // 短链接所在的自定义域名，为空表示默认域名
+	OriginalURL     *string    `json:"original_url,omitempty" validate:"omitempty,http_url"`
+	Title           *string    `json:"title,omitempty" validate:"omitempty,max=255"`
+	Notes           *string    `json:"notes,omitempty" validate:"omitempty,max=2000"`
+	ExpiredAt       *time.Time `json:"expired_at,omitempty" validate:"required_without_all=OriginalURL Title Notes ActiveFrom ClearActiveFrom RedirectType AlwaysPreview,omitempty,after"`
+	ActiveFrom      *time.Time `json:"active_from,omitempty"`
+	ClearActiveFrom bool       `json:"clear_active_from,omitempty" validate:"excluded_with=ActiveFrom"` // 取消定时生效，立即生效
+	RedirectType    *string    `json:"redirect_type,omitempty" validate:"omitempty,oneof=301 302 307 308 meta"`
+	AlwaysPreview   *bool      `json:"always_preview,omitempty"`
+	UserID          int        `json:"-"`
 }
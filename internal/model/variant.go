@@ -0,0 +1,50 @@
+package model
+
+// Variant A/B测试中短链接的一个跳转版本，按Weight占总权重的比例分配访客
+type Variant struct {
+	ID          int64  `json:"id"`
+	Destination string `json:"destination"`
+	Weight      int    `json:"weight"`
+}
+
+type CreateVariantRequest struct {
+	Code        string `param:"code" validate:"required,min=4,max=10,alphanum"`
+	Domain      string `query:"domain" json:"-" validate:"omitempty,fqdn,max=253"` // 短链接所在的自定义域名，为空表示默认域名
+	Destination string `json:"destination" validate:"required,http_url"`
+	Weight      int    `json:"weight" validate:"required,min=1,max=10000"`
+	UserID      int    `json:"-"`
+}
+
+type UpdateVariantRequest struct {
+	Code        string `param:"code" validate:"required,min=4,max=10,alphanum"`
+	Domain      string `query:"domain" json:"-" validate:"omitempty,fqdn,max=253"` // 短链接所在的自定义域名，为空表示默认域名
+	ID          int64  `param:"id" validate:"required"`
+	Destination string `json:"destination" validate:"required,http_url"`
+	Weight      int    `json:"weight" validate:"required,min=1,max=10000"`
+	UserID      int    `json:"-"`
+}
+
+type DeleteVariantRequest struct {
+	Code   string `param:"code" validate:"required,min=4,max=10,alphanum"`
+	Domain string `query:"domain" json:"-" validate:"omitempty,fqdn,max=253"` // 短链接所在的自定义域名，为空表示默认域名
+	ID     int64  `param:"id" validate:"required"`
+	UserID int    `json:"-"`
+}
+
+type GetVariantsRequest struct {
+	Code   string `param:"code" validate:"required,min=4,max=10,alphanum"`
+	Domain string `query:"domain" json:"-" validate:"omitempty,fqdn,max=253"` // 短链接所在的自定义域名，为空表示默认域名
+	UserID int    `json:"-"`
+}
+
+type GetVariantsResponse struct {
+	Items []Variant `json:"items"`
+}
+
+type VariantStats struct {
+	ID          int64  `json:"id"`
+	Destination string `json:"destination"`
+	Weight      int    `json:"weight"`
+	Clicks      int64  `json:"clicks"`
+	BotClicks   int64  `json:"bot_clicks"`
+}
@@ -5,17 +5,99 @@
 package repo
 
 import (
+	"database/sql"
 	"time"
 )
 
+type Click struct {
+	ID          int64         `json:"id"`
+	UrlID       int64         `json:"url_id"`
+	Destination string        `json:"destination"`
+	Referrer    string        `json:"referrer"`
+	UserAgent   string        `json:"user_agent"`
+	Ip          string        `json:"ip"`
+	ClickedAt   time.Time     `json:"clicked_at"`
+	Country     string        `json:"country"`
+	City        string        `json:"city"`
+	Device      string        `json:"device"`
+	Os          string        `json:"os"`
+	Browser     string        `json:"browser"`
+	IsBot       bool          `json:"is_bot"`
+	VariantID   sql.NullInt64 `json:"variant_id"`
+}
+
+type Domain struct {
+	ID          int64        `json:"id"`
+	UserID      int32        `json:"user_id"`
+	Host        string       `json:"host"`
+	VerifyToken string       `json:"verify_token"`
+	VerifiedAt  sql.NullTime `json:"verified_at"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+type Tag struct {
+	ID        int64     `json:"id"`
+	UserID    int32     `json:"user_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Url struct {
+	ID            int64         `json:"id"`
+	UserID        int32         `json:"user_id"`
+	OriginalUrl   string        `json:"original_url"`
+	ShortCode     string        `json:"short_code"`
+	IsCustom      bool          `json:"is_custom"`
+	Views         int32         `json:"views"`
+	ExpiredAt     time.Time     `json:"expired_at"`
+	CreatedAt     time.Time     `json:"created_at"`
+	PasswordHash  string        `json:"password_hash"`
+	MaxClicks     sql.NullInt32 `json:"max_clicks"`
+	RedirectCount int32         `json:"redirect_count"`
+	ActiveFrom    sql.NullTime  `json:"active_from"`
+	RedirectType  string        `json:"redirect_type"`
+	AlwaysPreview bool          `json:"always_preview"`
+	Title         string        `json:"title"`
+	Notes         string        `json:"notes"`
+	DeletedAt     sql.NullTime  `json:"deleted_at"`
+	DomainID      sql.NullInt64 `json:"domain_id"`
+	Disabled      bool          `json:"disabled"`
+	UrlHash       string        `json:"url_hash"`
+	RawUrl        string        `json:"raw_url"`
+}
+
+type UrlTag struct {
+	UrlID int64 `json:"url_id"`
+	TagID int64 `json:"tag_id"`
+}
+
+type UrlRevision struct {
+	ID           int64         `json:"id"`
+	UrlID        int64         `json:"url_id"`
+	OriginalUrl  string        `json:"original_url"`
+	Title        string        `json:"title"`
+	Notes        string        `json:"notes"`
+	UserID       int32         `json:"user_id"`
+	RestoredFrom sql.NullInt64 `json:"restored_from"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+type UrlRule struct {
+	ID             int64     `json:"id"`
+	UrlID          int64     `json:"url_id"`
+	Position       int32     `json:"position"`
+	ConditionType  string    `json:"condition_type"`
+	ConditionValue string    `json:"condition_value"`
+	Timezone       string    `json:"timezone"`
+	Destination    string    `json:"destination"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type UrlVariant struct {
 	ID          int64     `json:"id"`
-	UserID      int32     `json:"user_id"`
-	OriginalUrl string    `json:"original_url"`
-	ShortCode   string    `json:"short_code"`
-	IsCustom    bool      `json:"is_custom"`
-	Views       int32     `json:"views"`
-	ExpiredAt   time.Time `json:"expired_at"`
+	UrlID       int64     `json:"url_id"`
+	Destination string    `json:"destination"`
+	Weight      int32     `json:"weight"`
 	CreatedAt   time.Time `json:"created_at"`
 }
 
@@ -6,20 +6,93 @@ package repo
 
 import (
 	"context"
+	"database/sql"
 )
 
 type Querier interface {
-	CreateURL(ctx context.Context, arg CreateURLParams) error
+	CountClicksByCity(ctx context.Context, arg CountClicksByCityParams) ([]CountClicksByCityRow, error)
+	CountClicksByCountry(ctx context.Context, arg CountClicksByCountryParams) ([]CountClicksByCountryRow, error)
+	CountClicksByInterval(ctx context.Context, arg CountClicksByIntervalParams) ([]CountClicksByIntervalRow, error)
+	CountClicksByUserAgent(ctx context.Context, arg CountClicksByUserAgentParams) ([]CountClicksByUserAgentRow, error)
+	CountClicksByVariant(ctx context.Context, arg CountClicksByVariantParams) ([]CountClicksByVariantRow, error)
+	CountUserClicksByUserAgent(ctx context.Context, arg CountUserClicksByUserAgentParams) ([]CountUserClicksByUserAgentRow, error)
+	AttachTag(ctx context.Context, arg AttachTagParams) error
+	AttachTags(ctx context.Context, arg AttachTagsParams) error
+	BulkCreateURLs(ctx context.Context, arg BulkCreateURLsParams) ([]BulkCreateURLsRow, error)
+	ConsumeURLClick(ctx context.Context, id int64) (int32, error)
+	CountURLsByDomainID(ctx context.Context, domainID sql.NullInt64) (int64, error)
+	CountURLsByUserID(ctx context.Context, arg CountURLsByUserIDParams) (int64, error)
+	CreateClick(ctx context.Context, arg CreateClickParams) error
+	CreateDomain(ctx context.Context, arg CreateDomainParams) (Domain, error)
+	CreateInitialURLRevisions(ctx context.Context, urlIds []int64) error
+	CreateRule(ctx context.Context, arg CreateRuleParams) (UrlRule, error)
+	CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error)
+	CreateTagsIfNotExist(ctx context.Context, arg CreateTagsIfNotExistParams) error
+	CreateURL(ctx context.Context, arg CreateURLParams) (int64, error)
+	CreateURLRevision(ctx context.Context, arg CreateURLRevisionParams) (UrlRevision, error)
+	CreateVariant(ctx context.Context, arg CreateVariantParams) (UrlVariant, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (CreateUserRow, error)
-	DeleteURLByShortCode(ctx context.Context, shortCode string) error
-	GetURLsByUserID(ctx context.Context, arg GetURLsByUserIDParams) ([]GetURLsByUserIDRow, error)
-	GetUrlByShortCode(ctx context.Context, shortCode string) (GetUrlByShortCodeRow, error)
+	DeleteDomain(ctx context.Context, arg DeleteDomainParams) (int64, error)
+	DeleteRule(ctx context.Context, arg DeleteRuleParams) (int64, error)
+	DeleteTag(ctx context.Context, arg DeleteTagParams) (int64, error)
+	DeleteURLByID(ctx context.Context, id int64) error
+	DeleteVariant(ctx context.Context, arg DeleteVariantParams) (int64, error)
+	DetachTag(ctx context.Context, arg DetachTagParams) (int64, error)
+	DetachTagFromURLs(ctx context.Context, arg DetachTagFromURLsParams) error
+	ExportURLsByUserID(ctx context.Context, arg ExportURLsByUserIDParams) ([]ExportURLsByUserIDRow, error)
+	ExtendURLsExpiry(ctx context.Context, arg ExtendURLsExpiryParams) error
+	// 查找用户已有的、可以正常跳转的同一目标地址的非自定义短链接
+	GetActiveURLByHash(ctx context.Context, arg GetActiveURLByHashParams) (GetActiveURLByHashRow, error)
+	GetDomainByHost(ctx context.Context, arg GetDomainByHostParams) (Domain, error)
+	GetDomainByID(ctx context.Context, arg GetDomainByIDParams) (Domain, error)
+	GetTagIDByName(ctx context.Context, arg GetTagIDByNameParams) (int64, error)
+	GetTrashedURLByShortCodeAndUserID(ctx context.Context, arg GetTrashedURLByShortCodeAndUserIDParams) (GetTrashedURLByShortCodeAndUserIDRow, error)
+	GetTrashedURLsByUserID(ctx context.Context, arg GetTrashedURLsByUserIDParams) ([]GetTrashedURLsByUserIDRow, error)
+	GetURLByHostAndShortCode(ctx context.Context, arg GetURLByHostAndShortCodeParams) (GetURLByHostAndShortCodeRow, error)
+	// host为空表示默认域名
+	GetURLByShortCodeAndUserID(ctx context.Context, arg GetURLByShortCodeAndUserIDParams) (GetURLByShortCodeAndUserIDRow, error)
+	GetURLContentForUpdate(ctx context.Context, id int64) (GetURLContentForUpdateRow, error)
+	GetURLRevision(ctx context.Context, arg GetURLRevisionParams) (UrlRevision, error)
+	GetURLsByShortCodesAndUserID(ctx context.Context, arg GetURLsByShortCodesAndUserIDParams) ([]GetURLsByShortCodesAndUserIDRow, error)
+	// 每种排序方式和方向各用一个查询，游标条件和ORDER BY都是固定的列，才能使用索引
+	GetURLsByUserIDCreatedAtAsc(ctx context.Context, arg GetURLsByUserIDCreatedAtAscParams) ([]GetURLsByUserIDCreatedAtAscRow, error)
+	GetURLsByUserIDCreatedAtDesc(ctx context.Context, arg GetURLsByUserIDCreatedAtDescParams) ([]GetURLsByUserIDCreatedAtDescRow, error)
+	GetURLsByUserIDExpiredAtAsc(ctx context.Context, arg GetURLsByUserIDExpiredAtAscParams) ([]GetURLsByUserIDExpiredAtAscRow, error)
+	GetURLsByUserIDExpiredAtDesc(ctx context.Context, arg GetURLsByUserIDExpiredAtDescParams) ([]GetURLsByUserIDExpiredAtDescRow, error)
+	GetURLsByUserIDOriginalURLAsc(ctx context.Context, arg GetURLsByUserIDOriginalURLAscParams) ([]GetURLsByUserIDOriginalURLAscRow, error)
+	GetURLsByUserIDOriginalURLDesc(ctx context.Context, arg GetURLsByUserIDOriginalURLDescParams) ([]GetURLsByUserIDOriginalURLDescRow, error)
+	GetURLsByUserIDViewsAsc(ctx context.Context, arg GetURLsByUserIDViewsAscParams) ([]GetURLsByUserIDViewsAscRow, error)
+	GetURLsByUserIDViewsDesc(ctx context.Context, arg GetURLsByUserIDViewsDescParams) ([]GetURLsByUserIDViewsDescRow, error)
+	GetViewsByID(ctx context.Context, id int64) (int32, error)
 	GetUserByEmail(ctx context.Context, email string) (GetUserByEmailRow, error)
 	IsEmailAvaliable(ctx context.Context, email string) (bool, error)
-	IsShortCodeAvailable(ctx context.Context, shortCode string) (bool, error)
+	IsDomainHostVerified(ctx context.Context, host string) (bool, error)
+	IsShortCodeAvailable(ctx context.Context, arg IsShortCodeAvailableParams) (bool, error)
+	IsTagOwnedByUser(ctx context.Context, arg IsTagOwnedByUserParams) (bool, error)
+	ListDomainsByUserID(ctx context.Context, userID int32) ([]Domain, error)
+	ListRulesByURLID(ctx context.Context, urlID int64) ([]UrlRule, error)
+	ListTagsByURLIDs(ctx context.Context, urlIds []int64) ([]ListTagsByURLIDsRow, error)
+	ListTagsByNames(ctx context.Context, arg ListTagsByNamesParams) ([]Tag, error)
+	ListTagsByUserID(ctx context.Context, userID int32) ([]Tag, error)
+	ListURLRevisions(ctx context.Context, urlID int64) ([]UrlRevision, error)
+	// 迁移前创建的短链接还没有url_hash，由应用使用同一规范化规则回填
+	ListURLsWithoutHash(ctx context.Context, limit int32) ([]ListURLsWithoutHashRow, error)
+	ListVariantsByURLID(ctx context.Context, urlID int64) ([]UrlVariant, error)
+	PurgeTrashedURLs(ctx context.Context, arg PurgeTrashedURLsParams) ([]PurgeTrashedURLsRow, error)
+	RenameTag(ctx context.Context, arg RenameTagParams) (Tag, error)
+	RestoreURLByID(ctx context.Context, id int64) error
+	SetURLsDisabled(ctx context.Context, arg SetURLsDisabledParams) error
+	SetURLsExpiry(ctx context.Context, arg SetURLsExpiryParams) error
+	TrashURLByID(ctx context.Context, id int64) error
+	TrashURLsByIDs(ctx context.Context, ids []int64) error
 	UpdatePasswordByEmail(ctx context.Context, arg UpdatePasswordByEmailParams) (UpdatePasswordByEmailRow, error)
-	UpdateURLExpiredByShortCode(ctx context.Context, arg UpdateURLExpiredByShortCodeParams) error
-	UpdateViewsByShortCode(ctx context.Context, arg UpdateViewsByShortCodeParams) error
+	UpdateRule(ctx context.Context, arg UpdateRuleParams) (UrlRule, error)
+	UpdateURLContentByID(ctx context.Context, arg UpdateURLContentByIDParams) error
+	UpdateURLHash(ctx context.Context, arg UpdateURLHashParams) error
+	UpdateURLSettingsByID(ctx context.Context, arg UpdateURLSettingsByIDParams) error
+	UpdateVariant(ctx context.Context, arg UpdateVariantParams) (UrlVariant, error)
+	UpdateViewsByHostAndShortCode(ctx context.Context, arg UpdateViewsByHostAndShortCodeParams) error
+	VerifyDomain(ctx context.Context, id int64) (Domain, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -7,90 +7,456 @@ package repo
 
 import (
 	"context"
+	"database/sql"
 	"time"
+
+	"github.com/lib/pq"
 )
 
-const createURL = `-- name: CreateURL :exec
+const bulkCreateURLs = `-- name: BulkCreateURLs :many
 INSERT INTO urls (
     original_url,
     short_code,
     is_custom,
     expired_at,
-    user_id
-) VALUES (
-    $1, $2, $3, $4, $5
+    user_id,
+    title,
+    notes,
+    domain_id,
+    url_hash,
+    raw_url
 )
+SELECT u.original_url, u.short_code, u.is_custom, CURRENT_TIMESTAMP + u.ttl * INTERVAL '1 second', $1::int, u.title, u.notes, $2::bigint, u.url_hash, u.raw_url
+FROM unnest(
+    $3::text[],
+    $4::text[],
+    $5::boolean[],
+    $6::bigint[],
+    $7::text[],
+    $8::text[],
+    $9::text[],
+    $10::text[]
+) AS u(original_url, short_code, is_custom, ttl, title, notes, url_hash, raw_url)
+ON CONFLICT DO NOTHING
+RETURNING id, short_code
+`
+
+type BulkCreateURLsParams struct {
+	UserID       int32         `json:"user_id"`
+	DomainID     sql.NullInt64 `json:"domain_id"`
+	OriginalUrls []string      `json:"original_urls"`
+	ShortCodes   []string      `json:"short_codes"`
+	IsCustoms    []bool        `json:"is_customs"`
+	Ttls         []int64       `json:"ttls"`
+	Titles       []string      `json:"titles"`
+	Notes        []string      `json:"notes"`
+	UrlHashes    []string      `json:"url_hashes"`
+	RawUrls      []string      `json:"raw_urls"`
+}
+
+type BulkCreateURLsRow struct {
+	ID        int64  `json:"id"`
+	ShortCode string `json:"short_code"`
+}
+
+func (q *Queries) BulkCreateURLs(ctx context.Context, arg BulkCreateURLsParams) ([]BulkCreateURLsRow, error) {
+	rows, err := q.db.QueryContext(ctx, bulkCreateURLs,
+		arg.UserID,
+		arg.DomainID,
+		pq.Array(arg.OriginalUrls),
+		pq.Array(arg.ShortCodes),
+		pq.Array(arg.IsCustoms),
+		pq.Array(arg.Ttls),
+		pq.Array(arg.Titles),
+		pq.Array(arg.Notes),
+		pq.Array(arg.UrlHashes),
+		pq.Array(arg.RawUrls),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BulkCreateURLsRow
+	for rows.Next() {
+		var i BulkCreateURLsRow
+		if err := rows.Scan(&i.ID, &i.ShortCode); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const consumeURLClick = `-- name: ConsumeURLClick :one
+UPDATE urls
+SET redirect_count = redirect_count + 1
+WHERE id = $1
+AND deleted_at IS NULL
+AND NOT disabled
+AND expired_at > CURRENT_TIMESTAMP
+AND redirect_count < max_clicks
+RETURNING (max_clicks - redirect_count)::int AS remaining
+`
+
+func (q *Queries) ConsumeURLClick(ctx context.Context, id int64) (int32, error) {
+	row := q.db.QueryRowContext(ctx, consumeURLClick, id)
+	var remaining int32
+	err := row.Scan(&remaining)
+	return remaining, err
+}
+
+const countURLsByUserID = `-- name: CountURLsByUserID :one
+SELECT COUNT(*)
+FROM urls r
+WHERE r.user_id = $1
+AND r.deleted_at IS NULL
+AND (cardinality($2::citext[]) = 0 OR r.id IN (
+    SELECT ut.url_id
+    FROM url_tags ut
+    JOIN tags t ON t.id = ut.tag_id
+    WHERE t.user_id = $1
+    AND t.name = ANY($2::citext[])
+    GROUP BY ut.url_id
+    HAVING COUNT(*) = cardinality($2::citext[])
+))
+AND ($3::text IS NULL
+    OR r.original_url ILIKE $3::text
+    OR r.short_code ILIKE $3::text
+    OR r.title ILIKE $3::text
+    OR r.notes ILIKE $3::text)
+AND ($4::text IS NULL
+    OR ($4::text = 'active'
+        AND NOT r.disabled
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND (r.active_from IS NULL OR r.active_from <= CURRENT_TIMESTAMP)
+        AND (r.max_clicks IS NULL OR r.redirect_count < r.max_clicks))
+    OR ($4::text = 'expired'
+        AND (r.expired_at <= CURRENT_TIMESTAMP OR r.redirect_count >= COALESCE(r.max_clicks, r.redirect_count + 1)))
+    OR ($4::text = 'disabled'
+        AND r.disabled)
+    OR ($4::text = 'scheduled'
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND r.active_from > CURRENT_TIMESTAMP))
+AND ($5::boolean IS NULL OR r.is_custom = $5::boolean)
+AND ($6::timestamp IS NULL OR r.created_at >= $6::timestamp)
+AND ($7::timestamp IS NULL OR r.created_at < $7::timestamp)
+AND ($8::int IS NULL OR r.views >= $8::int)
+AND ($9::int IS NULL OR r.views <= $9::int)
+`
+
+type CountURLsByUserIDParams struct {
+	UserID      int32          `json:"user_id"`
+	Tags        []string       `json:"tags"`
+	Pattern     sql.NullString `json:"pattern"`
+	Status      sql.NullString `json:"status"`
+	IsCustom    sql.NullBool   `json:"is_custom"`
+	CreatedFrom sql.NullTime   `json:"created_from"`
+	CreatedTo   sql.NullTime   `json:"created_to"`
+	MinViews    sql.NullInt32  `json:"min_views"`
+	MaxViews    sql.NullInt32  `json:"max_views"`
+}
+
+func (q *Queries) CountURLsByUserID(ctx context.Context, arg CountURLsByUserIDParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countURLsByUserID,
+		arg.UserID,
+		pq.Array(arg.Tags),
+		arg.Pattern,
+		arg.Status,
+		arg.IsCustom,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.MinViews,
+		arg.MaxViews,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createURL = `-- name: CreateURL :one
+INSERT INTO urls (
+    original_url,
+    short_code,
+    is_custom,
+    expired_at,
+    user_id,
+    password_hash,
+    max_clicks,
+    active_from,
+    redirect_type,
+    always_preview,
+    title,
+    notes,
+    domain_id,
+    url_hash,
+    raw_url
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
+) RETURNING id
 `
 
 type CreateURLParams struct {
-	OriginalUrl string    `json:"original_url"`
-	ShortCode   string    `json:"short_code"`
-	IsCustom    bool      `json:"is_custom"`
-	ExpiredAt   time.Time `json:"expired_at"`
-	UserID      int32     `json:"user_id"`
+	OriginalUrl   string        `json:"original_url"`
+	ShortCode     string        `json:"short_code"`
+	IsCustom      bool          `json:"is_custom"`
+	ExpiredAt     time.Time     `json:"expired_at"`
+	UserID        int32         `json:"user_id"`
+	PasswordHash  string        `json:"password_hash"`
+	MaxClicks     sql.NullInt32 `json:"max_clicks"`
+	ActiveFrom    sql.NullTime  `json:"active_from"`
+	RedirectType  string        `json:"redirect_type"`
+	AlwaysPreview bool          `json:"always_preview"`
+	Title         string        `json:"title"`
+	Notes         string        `json:"notes"`
+	DomainID      sql.NullInt64 `json:"domain_id"`
+	UrlHash       string        `json:"url_hash"`
+	RawUrl        string        `json:"raw_url"`
 }
 
-func (q *Queries) CreateURL(ctx context.Context, arg CreateURLParams) error {
-	_, err := q.db.ExecContext(ctx, createURL,
+func (q *Queries) CreateURL(ctx context.Context, arg CreateURLParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createURL,
 		arg.OriginalUrl,
 		arg.ShortCode,
 		arg.IsCustom,
 		arg.ExpiredAt,
 		arg.UserID,
+		arg.PasswordHash,
+		arg.MaxClicks,
+		arg.ActiveFrom,
+		arg.RedirectType,
+		arg.AlwaysPreview,
+		arg.Title,
+		arg.Notes,
+		arg.DomainID,
+		arg.UrlHash,
+		arg.RawUrl,
 	)
-	return err
+	var id int64
+	err := row.Scan(&id)
+	return id, err
 }
 
-const deleteURLByShortCode = `-- name: DeleteURLByShortCode :exec
+const deleteURLByID = `-- name: DeleteURLByID :exec
 DELETE FROM urls
-WHERE short_code = $1
+WHERE id = $1
 `
 
-func (q *Queries) DeleteURLByShortCode(ctx context.Context, shortCode string) error {
-	_, err := q.db.ExecContext(ctx, deleteURLByShortCode, shortCode)
+func (q *Queries) DeleteURLByID(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteURLByID, id)
 	return err
 }
 
-const getURLsByUserID = `-- name: GetURLsByUserID :many
-SELECT id, original_url, short_code, views, is_custom, expired_at, COUNT(*) OVER() AS total
+const exportURLsByUserID = `-- name: ExportURLsByUserID :many
+SELECT r.id, r.original_url, r.short_code, r.title, r.notes, r.is_custom, r.views, r.created_at, r.expired_at, COALESCE(d.host, '')::text AS host,
+    COALESCE(array_agg(t.name ORDER BY t.name) FILTER (WHERE t.id IS NOT NULL), '{}')::text[] AS tags
 FROM urls r
+LEFT JOIN domains d ON d.id = r.domain_id
+LEFT JOIN url_tags ut ON ut.url_id = r.id
+LEFT JOIN tags t ON t.id = ut.tag_id
 WHERE r.user_id = $1
-ORDER BY created_at DESC
-LIMIT $2 OFFSET $3
+AND r.deleted_at IS NULL
+AND r.id > $2
+GROUP BY r.id, d.host
+ORDER BY r.id
+LIMIT $3
 `
 
-type GetURLsByUserIDParams struct {
+type ExportURLsByUserIDParams struct {
 	UserID int32 `json:"user_id"`
+	ID     int64 `json:"id"`
 	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
 }
 
-type GetURLsByUserIDRow struct {
+type ExportURLsByUserIDRow struct {
 	ID          int64     `json:"id"`
 	OriginalUrl string    `json:"original_url"`
 	ShortCode   string    `json:"short_code"`
-	Views       int32     `json:"views"`
+	Title       string    `json:"title"`
+	Notes       string    `json:"notes"`
 	IsCustom    bool      `json:"is_custom"`
+	Views       int32     `json:"views"`
+	CreatedAt   time.Time `json:"created_at"`
 	ExpiredAt   time.Time `json:"expired_at"`
-	Total       int64     `json:"total"`
+	Host        string    `json:"host"`
+	Tags        []string  `json:"tags"`
 }
 
-func (q *Queries) GetURLsByUserID(ctx context.Context, arg GetURLsByUserIDParams) ([]GetURLsByUserIDRow, error) {
-	rows, err := q.db.QueryContext(ctx, getURLsByUserID, arg.UserID, arg.Limit, arg.Offset)
+func (q *Queries) ExportURLsByUserID(ctx context.Context, arg ExportURLsByUserIDParams) ([]ExportURLsByUserIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, exportURLsByUserID, arg.UserID, arg.ID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []GetURLsByUserIDRow
+	var items []ExportURLsByUserIDRow
 	for rows.Next() {
-		var i GetURLsByUserIDRow
+		var i ExportURLsByUserIDRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.OriginalUrl,
 			&i.ShortCode,
-			&i.Views,
+			&i.Title,
+			&i.Notes,
 			&i.IsCustom,
+			&i.Views,
+			&i.CreatedAt,
 			&i.ExpiredAt,
+			&i.Host,
+			pq.Array(&i.Tags),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const extendURLsExpiry = `-- name: ExtendURLsExpiry :exec
+UPDATE urls
+SET expired_at = GREATEST(expired_at, CURRENT_TIMESTAMP) + $1::bigint * INTERVAL '1 second'
+WHERE id = ANY($2::bigint[])
+`
+
+type ExtendURLsExpiryParams struct {
+	Seconds int64   `json:"seconds"`
+	Ids     []int64 `json:"ids"`
+}
+
+func (q *Queries) ExtendURLsExpiry(ctx context.Context, arg ExtendURLsExpiryParams) error {
+	_, err := q.db.ExecContext(ctx, extendURLsExpiry, arg.Seconds, pq.Array(arg.Ids))
+	return err
+}
+
+const getActiveURLByHash = `-- name: GetActiveURLByHash :one
+SELECT u.short_code, COALESCE(d.host, '')::text AS host
+FROM urls u
+LEFT JOIN domains d ON d.id = u.domain_id
+WHERE u.user_id = $1
+AND u.url_hash = $2
+AND u.domain_id IS NOT DISTINCT FROM $3
+AND u.redirect_type = $4
+AND u.always_preview = $5
+AND NOT u.is_custom
+AND u.deleted_at IS NULL
+AND NOT u.disabled
+AND u.password_hash = ''
+AND u.max_clicks IS NULL
+AND u.active_from IS NULL
+AND u.expired_at > CURRENT_TIMESTAMP
+AND NOT EXISTS (SELECT 1 FROM url_rules r WHERE r.url_id = u.id)
+AND NOT EXISTS (SELECT 1 FROM url_variants v WHERE v.url_id = u.id)
+ORDER BY u.expired_at DESC
+LIMIT 1
+`
+
+type GetActiveURLByHashParams struct {
+	UserID        int32         `json:"user_id"`
+	UrlHash       string        `json:"url_hash"`
+	DomainID      sql.NullInt64 `json:"domain_id"`
+	RedirectType  string        `json:"redirect_type"`
+	AlwaysPreview bool          `json:"always_preview"`
+}
+
+type GetActiveURLByHashRow struct {
+	ShortCode string `json:"short_code"`
+	Host      string `json:"host"`
+}
+
+// 查找用户已有的、可以正常跳转的同一目标地址的非自定义短链接
+func (q *Queries) GetActiveURLByHash(ctx context.Context, arg GetActiveURLByHashParams) (GetActiveURLByHashRow, error) {
+	row := q.db.QueryRowContext(ctx, getActiveURLByHash,
+		arg.UserID,
+		arg.UrlHash,
+		arg.DomainID,
+		arg.RedirectType,
+		arg.AlwaysPreview,
+	)
+	var i GetActiveURLByHashRow
+	err := row.Scan(&i.ShortCode, &i.Host)
+	return i, err
+}
+
+const getTrashedURLByShortCodeAndUserID = `-- name: GetTrashedURLByShortCodeAndUserID :one
+SELECT u.id, COALESCE(d.host, '')::text AS host
+FROM urls u
+LEFT JOIN domains d ON d.id = u.domain_id
+WHERE u.short_code = $1
+AND u.user_id = $2
+AND COALESCE(d.host, '') = $3::citext
+AND u.deleted_at IS NOT NULL
+`
+
+type GetTrashedURLByShortCodeAndUserIDParams struct {
+	ShortCode string `json:"short_code"`
+	UserID    int32  `json:"user_id"`
+	Host      string `json:"host"`
+}
+
+type GetTrashedURLByShortCodeAndUserIDRow struct {
+	ID   int64  `json:"id"`
+	Host string `json:"host"`
+}
+
+func (q *Queries) GetTrashedURLByShortCodeAndUserID(ctx context.Context, arg GetTrashedURLByShortCodeAndUserIDParams) (GetTrashedURLByShortCodeAndUserIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getTrashedURLByShortCodeAndUserID, arg.ShortCode, arg.UserID, arg.Host)
+	var i GetTrashedURLByShortCodeAndUserIDRow
+	err := row.Scan(&i.ID, &i.Host)
+	return i, err
+}
+
+const getTrashedURLsByUserID = `-- name: GetTrashedURLsByUserID :many
+SELECT r.id, r.original_url, r.short_code, r.title, r.deleted_at, COALESCE(d.host, '')::text AS host, COUNT(*) OVER() AS total
+FROM urls r
+LEFT JOIN domains d ON d.id = r.domain_id
+WHERE r.user_id = $1
+AND r.deleted_at IS NOT NULL
+ORDER BY r.deleted_at DESC
+LIMIT $2 OFFSET $3
+`
+
+type GetTrashedURLsByUserIDParams struct {
+	UserID int32 `json:"user_id"`
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+type GetTrashedURLsByUserIDRow struct {
+	ID          int64        `json:"id"`
+	OriginalUrl string       `json:"original_url"`
+	ShortCode   string       `json:"short_code"`
+	Title       string       `json:"title"`
+	DeletedAt   sql.NullTime `json:"deleted_at"`
+	Host        string       `json:"host"`
+	Total       int64        `json:"total"`
+}
+
+func (q *Queries) GetTrashedURLsByUserID(ctx context.Context, arg GetTrashedURLsByUserIDParams) ([]GetTrashedURLsByUserIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTrashedURLsByUserID, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTrashedURLsByUserIDRow
+	for rows.Next() {
+		var i GetTrashedURLsByUserIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OriginalUrl,
+			&i.ShortCode,
+			&i.Title,
+			&i.DeletedAt,
+			&i.Host,
 			&i.Total,
 		); err != nil {
 			return nil, err
@@ -106,73 +472,1454 @@ func (q *Queries) GetURLsByUserID(ctx context.Context, arg GetURLsByUserIDParams
 	return items, nil
 }
 
-const getUrlByShortCode = `-- name: GetUrlByShortCode :one
-SELECT original_url, short_code, views, is_custom FROM urls 
-WHERE short_code = $1
-AND expired_at > CURRENT_TIMESTAMP
+const getURLByHostAndShortCode = `-- name: GetURLByHostAndShortCode :one
+SELECT u.id, u.original_url, u.short_code, u.views, u.is_custom, u.password_hash, u.max_clicks, u.active_from, u.redirect_type, u.always_preview, u.expired_at, u.created_at, COALESCE(d.host, '')::text AS host
+FROM urls u
+LEFT JOIN domains d ON d.id = u.domain_id
+WHERE u.short_code = $1
+AND u.domain_id IS NOT DISTINCT FROM (
+    SELECT id FROM domains
+    WHERE domains.host = $2
+    AND verified_at IS NOT NULL
+)
+AND u.deleted_at IS NULL
+AND NOT u.disabled
+AND u.expired_at > CURRENT_TIMESTAMP
+AND (u.max_clicks IS NULL OR u.redirect_count < u.max_clicks)
 `
 
-type GetUrlByShortCodeRow struct {
-	OriginalUrl string `json:"original_url"`
-	ShortCode   string `json:"short_code"`
-	Views       int32  `json:"views"`
-	IsCustom    bool   `json:"is_custom"`
+type GetURLByHostAndShortCodeParams struct {
+	ShortCode string `json:"short_code"`
+	Host      string `json:"host"`
 }
 
-func (q *Queries) GetUrlByShortCode(ctx context.Context, shortCode string) (GetUrlByShortCodeRow, error) {
-	row := q.db.QueryRowContext(ctx, getUrlByShortCode, shortCode)
-	var i GetUrlByShortCodeRow
+type GetURLByHostAndShortCodeRow struct {
+	ID            int64         `json:"id"`
+	OriginalUrl   string        `json:"original_url"`
+	ShortCode     string        `json:"short_code"`
+	Views         int32         `json:"views"`
+	IsCustom      bool          `json:"is_custom"`
+	PasswordHash  string        `json:"password_hash"`
+	MaxClicks     sql.NullInt32 `json:"max_clicks"`
+	ActiveFrom    sql.NullTime  `json:"active_from"`
+	RedirectType  string        `json:"redirect_type"`
+	AlwaysPreview bool          `json:"always_preview"`
+	ExpiredAt     time.Time     `json:"expired_at"`
+	CreatedAt     time.Time     `json:"created_at"`
+	Host          string        `json:"host"`
+}
+
+func (q *Queries) GetURLByHostAndShortCode(ctx context.Context, arg GetURLByHostAndShortCodeParams) (GetURLByHostAndShortCodeRow, error) {
+	row := q.db.QueryRowContext(ctx, getURLByHostAndShortCode, arg.ShortCode, arg.Host)
+	var i GetURLByHostAndShortCodeRow
 	err := row.Scan(
+		&i.ID,
 		&i.OriginalUrl,
 		&i.ShortCode,
 		&i.Views,
 		&i.IsCustom,
+		&i.PasswordHash,
+		&i.MaxClicks,
+		&i.ActiveFrom,
+		&i.RedirectType,
+		&i.AlwaysPreview,
+		&i.ExpiredAt,
+		&i.CreatedAt,
+		&i.Host,
 	)
 	return i, err
 }
 
-const isShortCodeAvailable = `-- name: IsShortCodeAvailable :one
-SELECT NOT EXISTS(
-    SELECT 1 FROM urls
-    WHERE short_code = $1
-) AS is_available
+const getURLByShortCodeAndUserID = `-- name: GetURLByShortCodeAndUserID :one
+SELECT u.id, COALESCE(d.host, '')::text AS host
+FROM urls u
+LEFT JOIN domains d ON d.id = u.domain_id
+WHERE u.short_code = $1
+AND u.user_id = $2
+AND COALESCE(d.host, '') = $3::citext
+AND u.deleted_at IS NULL
 `
 
-func (q *Queries) IsShortCodeAvailable(ctx context.Context, shortCode string) (bool, error) {
-	row := q.db.QueryRowContext(ctx, isShortCodeAvailable, shortCode)
-	var is_available bool
-	err := row.Scan(&is_available)
-	return is_available, err
+type GetURLByShortCodeAndUserIDParams struct {
+	ShortCode string `json:"short_code"`
+	UserID    int32  `json:"user_id"`
+	Host      string `json:"host"`
 }
 
-const updateURLExpiredByShortCode = `-- name: UpdateURLExpiredByShortCode :exec
-UPDATE urls
-SET expired_at = $1
-WHERE short_code = $2
+type GetURLByShortCodeAndUserIDRow struct {
+	ID   int64  `json:"id"`
+	Host string `json:"host"`
+}
+
+// host为空表示默认域名
+func (q *Queries) GetURLByShortCodeAndUserID(ctx context.Context, arg GetURLByShortCodeAndUserIDParams) (GetURLByShortCodeAndUserIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getURLByShortCodeAndUserID, arg.ShortCode, arg.UserID, arg.Host)
+	var i GetURLByShortCodeAndUserIDRow
+	err := row.Scan(&i.ID, &i.Host)
+	return i, err
+}
+
+const getURLContentForUpdate = `-- name: GetURLContentForUpdate :one
+SELECT original_url, title, notes FROM urls
+WHERE id = $1
+FOR UPDATE
 `
 
-type UpdateURLExpiredByShortCodeParams struct {
-	ExpiredAt time.Time `json:"expired_at"`
-	ShortCode string    `json:"short_code"`
+type GetURLContentForUpdateRow struct {
+	OriginalUrl string `json:"original_url"`
+	Title       string `json:"title"`
+	Notes       string `json:"notes"`
 }
 
-func (q *Queries) UpdateURLExpiredByShortCode(ctx context.Context, arg UpdateURLExpiredByShortCodeParams) error {
-	_, err := q.db.ExecContext(ctx, updateURLExpiredByShortCode, arg.ExpiredAt, arg.ShortCode)
-	return err
+func (q *Queries) GetURLContentForUpdate(ctx context.Context, id int64) (GetURLContentForUpdateRow, error) {
+	row := q.db.QueryRowContext(ctx, getURLContentForUpdate, id)
+	var i GetURLContentForUpdateRow
+	err := row.Scan(&i.OriginalUrl, &i.Title, &i.Notes)
+	return i, err
 }
 
-const updateViewsByShortCode = `-- name: UpdateViewsByShortCode :exec
-UPDATE urls
-SET views = views + $1
-WHERE short_code = $2
+const getURLsByShortCodesAndUserID = `-- name: GetURLsByShortCodesAndUserID :many
+SELECT u.id, u.short_code, COALESCE(d.host, '')::text AS host
+FROM urls u
+LEFT JOIN domains d ON d.id = u.domain_id
+WHERE u.short_code = ANY($1::text[])
+AND u.user_id = $2
+AND COALESCE(d.host, '') = $3::citext
+AND u.deleted_at IS NULL
+`
+
+type GetURLsByShortCodesAndUserIDParams struct {
+	ShortCodes []string `json:"short_codes"`
+	UserID     int32    `json:"user_id"`
+	Host       string   `json:"host"`
+}
+
+type GetURLsByShortCodesAndUserIDRow struct {
+	ID        int64  `json:"id"`
+	ShortCode string `json:"short_code"`
+	Host      string `json:"host"`
+}
+
+func (q *Queries) GetURLsByShortCodesAndUserID(ctx context.Context, arg GetURLsByShortCodesAndUserIDParams) ([]GetURLsByShortCodesAndUserIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getURLsByShortCodesAndUserID, pq.Array(arg.ShortCodes), arg.UserID, arg.Host)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetURLsByShortCodesAndUserIDRow
+	for rows.Next() {
+		var i GetURLsByShortCodesAndUserIDRow
+		if err := rows.Scan(&i.ID, &i.ShortCode, &i.Host); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getURLsByUserIDCreatedAtAsc = `-- name: GetURLsByUserIDCreatedAtAsc :many
+SELECT r.id, r.original_url, r.raw_url, r.short_code, r.views, r.is_custom, r.expired_at, r.max_clicks, r.redirect_count, r.title, r.notes, r.disabled, r.created_at, COALESCE(d.host, '')::text AS host
+FROM urls r
+LEFT JOIN domains d ON d.id = r.domain_id
+WHERE r.user_id = $1
+AND r.deleted_at IS NULL
+AND (cardinality($2::citext[]) = 0 OR r.id IN (
+    SELECT ut.url_id
+    FROM url_tags ut
+    JOIN tags t ON t.id = ut.tag_id
+    WHERE t.user_id = $1
+    AND t.name = ANY($2::citext[])
+    GROUP BY ut.url_id
+    HAVING COUNT(*) = cardinality($2::citext[])
+))
+AND ($3::text IS NULL
+    OR r.original_url ILIKE $3::text
+    OR r.short_code ILIKE $3::text
+    OR r.title ILIKE $3::text
+    OR r.notes ILIKE $3::text)
+AND ($4::text IS NULL
+    OR ($4::text = 'active'
+        AND NOT r.disabled
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND (r.active_from IS NULL OR r.active_from <= CURRENT_TIMESTAMP)
+        AND (r.max_clicks IS NULL OR r.redirect_count < r.max_clicks))
+    OR ($4::text = 'expired'
+        AND (r.expired_at <= CURRENT_TIMESTAMP OR r.redirect_count >= COALESCE(r.max_clicks, r.redirect_count + 1)))
+    OR ($4::text = 'disabled'
+        AND r.disabled)
+    OR ($4::text = 'scheduled'
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND r.active_from > CURRENT_TIMESTAMP))
+AND ($5::boolean IS NULL OR r.is_custom = $5::boolean)
+AND ($6::timestamp IS NULL OR r.created_at >= $6::timestamp)
+AND ($7::timestamp IS NULL OR r.created_at < $7::timestamp)
+AND ($8::int IS NULL OR r.views >= $8::int)
+AND ($9::int IS NULL OR r.views <= $9::int)
+AND ($10::bigint IS NULL OR (r.created_at, r.id) > ($11::timestamp, $10::bigint))
+ORDER BY r.created_at, r.id
+LIMIT $12 OFFSET $13
+`
+
+type GetURLsByUserIDCreatedAtAscParams struct {
+	UserID      int32          `json:"user_id"`
+	Tags        []string       `json:"tags"`
+	Pattern     sql.NullString `json:"pattern"`
+	Status      sql.NullString `json:"status"`
+	IsCustom    sql.NullBool   `json:"is_custom"`
+	CreatedFrom sql.NullTime   `json:"created_from"`
+	CreatedTo   sql.NullTime   `json:"created_to"`
+	MinViews    sql.NullInt32  `json:"min_views"`
+	MaxViews    sql.NullInt32  `json:"max_views"`
+	CursorID    sql.NullInt64  `json:"cursor_id"`
+	CursorTime  sql.NullTime   `json:"cursor_time"`
+	Limit       int32          `json:"limit"`
+	Offset      int32          `json:"offset"`
+}
+
+type GetURLsByUserIDCreatedAtAscRow struct {
+	ID            int64         `json:"id"`
+	OriginalUrl   string        `json:"original_url"`
+	RawUrl        string        `json:"raw_url"`
+	ShortCode     string        `json:"short_code"`
+	Views         int32         `json:"views"`
+	IsCustom      bool          `json:"is_custom"`
+	ExpiredAt     time.Time     `json:"expired_at"`
+	MaxClicks     sql.NullInt32 `json:"max_clicks"`
+	RedirectCount int32         `json:"redirect_count"`
+	Title         string        `json:"title"`
+	Notes         string        `json:"notes"`
+	Disabled      bool          `json:"disabled"`
+	CreatedAt     time.Time     `json:"created_at"`
+	Host          string        `json:"host"`
+}
+
+// 每种排序方式和方向各用一个查询，游标条件和ORDER BY都是固定的列，才能使用索引
+func (q *Queries) GetURLsByUserIDCreatedAtAsc(ctx context.Context, arg GetURLsByUserIDCreatedAtAscParams) ([]GetURLsByUserIDCreatedAtAscRow, error) {
+	rows, err := q.db.QueryContext(ctx, getURLsByUserIDCreatedAtAsc,
+		arg.UserID,
+		pq.Array(arg.Tags),
+		arg.Pattern,
+		arg.Status,
+		arg.IsCustom,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.MinViews,
+		arg.MaxViews,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetURLsByUserIDCreatedAtAscRow
+	for rows.Next() {
+		var i GetURLsByUserIDCreatedAtAscRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OriginalUrl,
+			&i.RawUrl,
+			&i.ShortCode,
+			&i.Views,
+			&i.IsCustom,
+			&i.ExpiredAt,
+			&i.MaxClicks,
+			&i.RedirectCount,
+			&i.Title,
+			&i.Notes,
+			&i.Disabled,
+			&i.CreatedAt,
+			&i.Host,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getURLsByUserIDCreatedAtDesc = `-- name: GetURLsByUserIDCreatedAtDesc :many
+SELECT r.id, r.original_url, r.raw_url, r.short_code, r.views, r.is_custom, r.expired_at, r.max_clicks, r.redirect_count, r.title, r.notes, r.disabled, r.created_at, COALESCE(d.host, '')::text AS host
+FROM urls r
+LEFT JOIN domains d ON d.id = r.domain_id
+WHERE r.user_id = $1
+AND r.deleted_at IS NULL
+AND (cardinality($2::citext[]) = 0 OR r.id IN (
+    SELECT ut.url_id
+    FROM url_tags ut
+    JOIN tags t ON t.id = ut.tag_id
+    WHERE t.user_id = $1
+    AND t.name = ANY($2::citext[])
+    GROUP BY ut.url_id
+    HAVING COUNT(*) = cardinality($2::citext[])
+))
+AND ($3::text IS NULL
+    OR r.original_url ILIKE $3::text
+    OR r.short_code ILIKE $3::text
+    OR r.title ILIKE $3::text
+    OR r.notes ILIKE $3::text)
+AND ($4::text IS NULL
+    OR ($4::text = 'active'
+        AND NOT r.disabled
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND (r.active_from IS NULL OR r.active_from <= CURRENT_TIMESTAMP)
+        AND (r.max_clicks IS NULL OR r.redirect_count < r.max_clicks))
+    OR ($4::text = 'expired'
+        AND (r.expired_at <= CURRENT_TIMESTAMP OR r.redirect_count >= COALESCE(r.max_clicks, r.redirect_count + 1)))
+    OR ($4::text = 'disabled'
+        AND r.disabled)
+    OR ($4::text = 'scheduled'
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND r.active_from > CURRENT_TIMESTAMP))
+AND ($5::boolean IS NULL OR r.is_custom = $5::boolean)
+AND ($6::timestamp IS NULL OR r.created_at >= $6::timestamp)
+AND ($7::timestamp IS NULL OR r.created_at < $7::timestamp)
+AND ($8::int IS NULL OR r.views >= $8::int)
+AND ($9::int IS NULL OR r.views <= $9::int)
+AND ($10::bigint IS NULL OR (r.created_at, r.id) < ($11::timestamp, $10::bigint))
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT $12 OFFSET $13
+`
+
+type GetURLsByUserIDCreatedAtDescParams struct {
+	UserID      int32          `json:"user_id"`
+	Tags        []string       `json:"tags"`
+	Pattern     sql.NullString `json:"pattern"`
+	Status      sql.NullString `json:"status"`
+	IsCustom    sql.NullBool   `json:"is_custom"`
+	CreatedFrom sql.NullTime   `json:"created_from"`
+	CreatedTo   sql.NullTime   `json:"created_to"`
+	MinViews    sql.NullInt32  `json:"min_views"`
+	MaxViews    sql.NullInt32  `json:"max_views"`
+	CursorID    sql.NullInt64  `json:"cursor_id"`
+	CursorTime  sql.NullTime   `json:"cursor_time"`
+	Limit       int32          `json:"limit"`
+	Offset      int32          `json:"offset"`
+}
+
+type GetURLsByUserIDCreatedAtDescRow struct {
+	ID            int64         `json:"id"`
+	OriginalUrl   string        `json:"original_url"`
+	RawUrl        string        `json:"raw_url"`
+	ShortCode     string        `json:"short_code"`
+	Views         int32         `json:"views"`
+	IsCustom      bool          `json:"is_custom"`
+	ExpiredAt     time.Time     `json:"expired_at"`
+	MaxClicks     sql.NullInt32 `json:"max_clicks"`
+	RedirectCount int32         `json:"redirect_count"`
+	Title         string        `json:"title"`
+	Notes         string        `json:"notes"`
+	Disabled      bool          `json:"disabled"`
+	CreatedAt     time.Time     `json:"created_at"`
+	Host          string        `json:"host"`
+}
+
+func (q *Queries) GetURLsByUserIDCreatedAtDesc(ctx context.Context, arg GetURLsByUserIDCreatedAtDescParams) ([]GetURLsByUserIDCreatedAtDescRow, error) {
+	rows, err := q.db.QueryContext(ctx, getURLsByUserIDCreatedAtDesc,
+		arg.UserID,
+		pq.Array(arg.Tags),
+		arg.Pattern,
+		arg.Status,
+		arg.IsCustom,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.MinViews,
+		arg.MaxViews,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetURLsByUserIDCreatedAtDescRow
+	for rows.Next() {
+		var i GetURLsByUserIDCreatedAtDescRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OriginalUrl,
+			&i.RawUrl,
+			&i.ShortCode,
+			&i.Views,
+			&i.IsCustom,
+			&i.ExpiredAt,
+			&i.MaxClicks,
+			&i.RedirectCount,
+			&i.Title,
+			&i.Notes,
+			&i.Disabled,
+			&i.CreatedAt,
+			&i.Host,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getURLsByUserIDExpiredAtAsc = `-- name: GetURLsByUserIDExpiredAtAsc :many
+SELECT r.id, r.original_url, r.raw_url, r.short_code, r.views, r.is_custom, r.expired_at, r.max_clicks, r.redirect_count, r.title, r.notes, r.disabled, r.created_at, COALESCE(d.host, '')::text AS host
+FROM urls r
+LEFT JOIN domains d ON d.id = r.domain_id
+WHERE r.user_id = $1
+AND r.deleted_at IS NULL
+AND (cardinality($2::citext[]) = 0 OR r.id IN (
+    SELECT ut.url_id
+    FROM url_tags ut
+    JOIN tags t ON t.id = ut.tag_id
+    WHERE t.user_id = $1
+    AND t.name = ANY($2::citext[])
+    GROUP BY ut.url_id
+    HAVING COUNT(*) = cardinality($2::citext[])
+))
+AND ($3::text IS NULL
+    OR r.original_url ILIKE $3::text
+    OR r.short_code ILIKE $3::text
+    OR r.title ILIKE $3::text
+    OR r.notes ILIKE $3::text)
+AND ($4::text IS NULL
+    OR ($4::text = 'active'
+        AND NOT r.disabled
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND (r.active_from IS NULL OR r.active_from <= CURRENT_TIMESTAMP)
+        AND (r.max_clicks IS NULL OR r.redirect_count < r.max_clicks))
+    OR ($4::text = 'expired'
+        AND (r.expired_at <= CURRENT_TIMESTAMP OR r.redirect_count >= COALESCE(r.max_clicks, r.redirect_count + 1)))
+    OR ($4::text = 'disabled'
+        AND r.disabled)
+    OR ($4::text = 'scheduled'
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND r.active_from > CURRENT_TIMESTAMP))
+AND ($5::boolean IS NULL OR r.is_custom = $5::boolean)
+AND ($6::timestamp IS NULL OR r.created_at >= $6::timestamp)
+AND ($7::timestamp IS NULL OR r.created_at < $7::timestamp)
+AND ($8::int IS NULL OR r.views >= $8::int)
+AND ($9::int IS NULL OR r.views <= $9::int)
+AND ($10::bigint IS NULL OR (r.expired_at, r.id) > ($11::timestamp, $10::bigint))
+ORDER BY r.expired_at, r.id
+LIMIT $12 OFFSET $13
+`
+
+type GetURLsByUserIDExpiredAtAscParams struct {
+	UserID      int32          `json:"user_id"`
+	Tags        []string       `json:"tags"`
+	Pattern     sql.NullString `json:"pattern"`
+	Status      sql.NullString `json:"status"`
+	IsCustom    sql.NullBool   `json:"is_custom"`
+	CreatedFrom sql.NullTime   `json:"created_from"`
+	CreatedTo   sql.NullTime   `json:"created_to"`
+	MinViews    sql.NullInt32  `json:"min_views"`
+	MaxViews    sql.NullInt32  `json:"max_views"`
+	CursorID    sql.NullInt64  `json:"cursor_id"`
+	CursorTime  sql.NullTime   `json:"cursor_time"`
+	Limit       int32          `json:"limit"`
+	Offset      int32          `json:"offset"`
+}
+
+type GetURLsByUserIDExpiredAtAscRow struct {
+	ID            int64         `json:"id"`
+	OriginalUrl   string        `json:"original_url"`
+	RawUrl        string        `json:"raw_url"`
+	ShortCode     string        `json:"short_code"`
+	Views         int32         `json:"views"`
+	IsCustom      bool          `json:"is_custom"`
+	ExpiredAt     time.Time     `json:"expired_at"`
+	MaxClicks     sql.NullInt32 `json:"max_clicks"`
+	RedirectCount int32         `json:"redirect_count"`
+	Title         string        `json:"title"`
+	Notes         string        `json:"notes"`
+	Disabled      bool          `json:"disabled"`
+	CreatedAt     time.Time     `json:"created_at"`
+	Host          string        `json:"host"`
+}
+
+func (q *Queries) GetURLsByUserIDExpiredAtAsc(ctx context.Context, arg GetURLsByUserIDExpiredAtAscParams) ([]GetURLsByUserIDExpiredAtAscRow, error) {
+	rows, err := q.db.QueryContext(ctx, getURLsByUserIDExpiredAtAsc,
+		arg.UserID,
+		pq.Array(arg.Tags),
+		arg.Pattern,
+		arg.Status,
+		arg.IsCustom,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.MinViews,
+		arg.MaxViews,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetURLsByUserIDExpiredAtAscRow
+	for rows.Next() {
+		var i GetURLsByUserIDExpiredAtAscRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OriginalUrl,
+			&i.RawUrl,
+			&i.ShortCode,
+			&i.Views,
+			&i.IsCustom,
+			&i.ExpiredAt,
+			&i.MaxClicks,
+			&i.RedirectCount,
+			&i.Title,
+			&i.Notes,
+			&i.Disabled,
+			&i.CreatedAt,
+			&i.Host,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getURLsByUserIDExpiredAtDesc = `-- name: GetURLsByUserIDExpiredAtDesc :many
+SELECT r.id, r.original_url, r.raw_url, r.short_code, r.views, r.is_custom, r.expired_at, r.max_clicks, r.redirect_count, r.title, r.notes, r.disabled, r.created_at, COALESCE(d.host, '')::text AS host
+FROM urls r
+LEFT JOIN domains d ON d.id = r.domain_id
+WHERE r.user_id = $1
+AND r.deleted_at IS NULL
+AND (cardinality($2::citext[]) = 0 OR r.id IN (
+    SELECT ut.url_id
+    FROM url_tags ut
+    JOIN tags t ON t.id = ut.tag_id
+    WHERE t.user_id = $1
+    AND t.name = ANY($2::citext[])
+    GROUP BY ut.url_id
+    HAVING COUNT(*) = cardinality($2::citext[])
+))
+AND ($3::text IS NULL
+    OR r.original_url ILIKE $3::text
+    OR r.short_code ILIKE $3::text
+    OR r.title ILIKE $3::text
+    OR r.notes ILIKE $3::text)
+AND ($4::text IS NULL
+    OR ($4::text = 'active'
+        AND NOT r.disabled
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND (r.active_from IS NULL OR r.active_from <= CURRENT_TIMESTAMP)
+        AND (r.max_clicks IS NULL OR r.redirect_count < r.max_clicks))
+    OR ($4::text = 'expired'
+        AND (r.expired_at <= CURRENT_TIMESTAMP OR r.redirect_count >= COALESCE(r.max_clicks, r.redirect_count + 1)))
+    OR ($4::text = 'disabled'
+        AND r.disabled)
+    OR ($4::text = 'scheduled'
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND r.active_from > CURRENT_TIMESTAMP))
+AND ($5::boolean IS NULL OR r.is_custom = $5::boolean)
+AND ($6::timestamp IS NULL OR r.created_at >= $6::timestamp)
+AND ($7::timestamp IS NULL OR r.created_at < $7::timestamp)
+AND ($8::int IS NULL OR r.views >= $8::int)
+AND ($9::int IS NULL OR r.views <= $9::int)
+AND ($10::bigint IS NULL OR (r.expired_at, r.id) < ($11::timestamp, $10::bigint))
+ORDER BY r.expired_at DESC, r.id DESC
+LIMIT $12 OFFSET $13
+`
+
+type GetURLsByUserIDExpiredAtDescParams struct {
+	UserID      int32          `json:"user_id"`
+	Tags        []string       `json:"tags"`
+	Pattern     sql.NullString `json:"pattern"`
+	Status      sql.NullString `json:"status"`
+	IsCustom    sql.NullBool   `json:"is_custom"`
+	CreatedFrom sql.NullTime   `json:"created_from"`
+	CreatedTo   sql.NullTime   `json:"created_to"`
+	MinViews    sql.NullInt32  `json:"min_views"`
+	MaxViews    sql.NullInt32  `json:"max_views"`
+	CursorID    sql.NullInt64  `json:"cursor_id"`
+	CursorTime  sql.NullTime   `json:"cursor_time"`
+	Limit       int32          `json:"limit"`
+	Offset      int32          `json:"offset"`
+}
+
+type GetURLsByUserIDExpiredAtDescRow struct {
+	ID            int64         `json:"id"`
+	OriginalUrl   string        `json:"original_url"`
+	RawUrl        string        `json:"raw_url"`
+	ShortCode     string        `json:"short_code"`
+	Views         int32         `json:"views"`
+	IsCustom      bool          `json:"is_custom"`
+	ExpiredAt     time.Time     `json:"expired_at"`
+	MaxClicks     sql.NullInt32 `json:"max_clicks"`
+	RedirectCount int32         `json:"redirect_count"`
+	Title         string        `json:"title"`
+	Notes         string        `json:"notes"`
+	Disabled      bool          `json:"disabled"`
+	CreatedAt     time.Time     `json:"created_at"`
+	Host          string        `json:"host"`
+}
+
+func (q *Queries) GetURLsByUserIDExpiredAtDesc(ctx context.Context, arg GetURLsByUserIDExpiredAtDescParams) ([]GetURLsByUserIDExpiredAtDescRow, error) {
+	rows, err := q.db.QueryContext(ctx, getURLsByUserIDExpiredAtDesc,
+		arg.UserID,
+		pq.Array(arg.Tags),
+		arg.Pattern,
+		arg.Status,
+		arg.IsCustom,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.MinViews,
+		arg.MaxViews,
+		arg.CursorID,
+		arg.CursorTime,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetURLsByUserIDExpiredAtDescRow
+	for rows.Next() {
+		var i GetURLsByUserIDExpiredAtDescRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OriginalUrl,
+			&i.RawUrl,
+			&i.ShortCode,
+			&i.Views,
+			&i.IsCustom,
+			&i.ExpiredAt,
+			&i.MaxClicks,
+			&i.RedirectCount,
+			&i.Title,
+			&i.Notes,
+			&i.Disabled,
+			&i.CreatedAt,
+			&i.Host,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getURLsByUserIDOriginalURLAsc = `-- name: GetURLsByUserIDOriginalURLAsc :many
+SELECT r.id, r.original_url, r.raw_url, r.short_code, r.views, r.is_custom, r.expired_at, r.max_clicks, r.redirect_count, r.title, r.notes, r.disabled, r.created_at, COALESCE(d.host, '')::text AS host
+FROM urls r
+LEFT JOIN domains d ON d.id = r.domain_id
+WHERE r.user_id = $1
+AND r.deleted_at IS NULL
+AND (cardinality($2::citext[]) = 0 OR r.id IN (
+    SELECT ut.url_id
+    FROM url_tags ut
+    JOIN tags t ON t.id = ut.tag_id
+    WHERE t.user_id = $1
+    AND t.name = ANY($2::citext[])
+    GROUP BY ut.url_id
+    HAVING COUNT(*) = cardinality($2::citext[])
+))
+AND ($3::text IS NULL
+    OR r.original_url ILIKE $3::text
+    OR r.short_code ILIKE $3::text
+    OR r.title ILIKE $3::text
+    OR r.notes ILIKE $3::text)
+AND ($4::text IS NULL
+    OR ($4::text = 'active'
+        AND NOT r.disabled
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND (r.active_from IS NULL OR r.active_from <= CURRENT_TIMESTAMP)
+        AND (r.max_clicks IS NULL OR r.redirect_count < r.max_clicks))
+    OR ($4::text = 'expired'
+        AND (r.expired_at <= CURRENT_TIMESTAMP OR r.redirect_count >= COALESCE(r.max_clicks, r.redirect_count + 1)))
+    OR ($4::text = 'disabled'
+        AND r.disabled)
+    OR ($4::text = 'scheduled'
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND r.active_from > CURRENT_TIMESTAMP))
+AND ($5::boolean IS NULL OR r.is_custom = $5::boolean)
+AND ($6::timestamp IS NULL OR r.created_at >= $6::timestamp)
+AND ($7::timestamp IS NULL OR r.created_at < $7::timestamp)
+AND ($8::int IS NULL OR r.views >= $8::int)
+AND ($9::int IS NULL OR r.views <= $9::int)
+AND ($10::bigint IS NULL OR (r.original_url, r.id) > ($11::text, $10::bigint))
+ORDER BY r.original_url, r.id
+LIMIT $12 OFFSET $13
+`
+
+type GetURLsByUserIDOriginalURLAscParams struct {
+	UserID      int32          `json:"user_id"`
+	Tags        []string       `json:"tags"`
+	Pattern     sql.NullString `json:"pattern"`
+	Status      sql.NullString `json:"status"`
+	IsCustom    sql.NullBool   `json:"is_custom"`
+	CreatedFrom sql.NullTime   `json:"created_from"`
+	CreatedTo   sql.NullTime   `json:"created_to"`
+	MinViews    sql.NullInt32  `json:"min_views"`
+	MaxViews    sql.NullInt32  `json:"max_views"`
+	CursorID    sql.NullInt64  `json:"cursor_id"`
+	CursorText  sql.NullString `json:"cursor_text"`
+	Limit       int32          `json:"limit"`
+	Offset      int32          `json:"offset"`
+}
+
+type GetURLsByUserIDOriginalURLAscRow struct {
+	ID            int64         `json:"id"`
+	OriginalUrl   string        `json:"original_url"`
+	RawUrl        string        `json:"raw_url"`
+	ShortCode     string        `json:"short_code"`
+	Views         int32         `json:"views"`
+	IsCustom      bool          `json:"is_custom"`
+	ExpiredAt     time.Time     `json:"expired_at"`
+	MaxClicks     sql.NullInt32 `json:"max_clicks"`
+	RedirectCount int32         `json:"redirect_count"`
+	Title         string        `json:"title"`
+	Notes         string        `json:"notes"`
+	Disabled      bool          `json:"disabled"`
+	CreatedAt     time.Time     `json:"created_at"`
+	Host          string        `json:"host"`
+}
+
+func (q *Queries) GetURLsByUserIDOriginalURLAsc(ctx context.Context, arg GetURLsByUserIDOriginalURLAscParams) ([]GetURLsByUserIDOriginalURLAscRow, error) {
+	rows, err := q.db.QueryContext(ctx, getURLsByUserIDOriginalURLAsc,
+		arg.UserID,
+		pq.Array(arg.Tags),
+		arg.Pattern,
+		arg.Status,
+		arg.IsCustom,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.MinViews,
+		arg.MaxViews,
+		arg.CursorID,
+		arg.CursorText,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetURLsByUserIDOriginalURLAscRow
+	for rows.Next() {
+		var i GetURLsByUserIDOriginalURLAscRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OriginalUrl,
+			&i.RawUrl,
+			&i.ShortCode,
+			&i.Views,
+			&i.IsCustom,
+			&i.ExpiredAt,
+			&i.MaxClicks,
+			&i.RedirectCount,
+			&i.Title,
+			&i.Notes,
+			&i.Disabled,
+			&i.CreatedAt,
+			&i.Host,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getURLsByUserIDOriginalURLDesc = `-- name: GetURLsByUserIDOriginalURLDesc :many
+SELECT r.id, r.original_url, r.raw_url, r.short_code, r.views, r.is_custom, r.expired_at, r.max_clicks, r.redirect_count, r.title, r.notes, r.disabled, r.created_at, COALESCE(d.host, '')::text AS host
+FROM urls r
+LEFT JOIN domains d ON d.id = r.domain_id
+WHERE r.user_id = $1
+AND r.deleted_at IS NULL
+AND (cardinality($2::citext[]) = 0 OR r.id IN (
+    SELECT ut.url_id
+    FROM url_tags ut
+    JOIN tags t ON t.id = ut.tag_id
+    WHERE t.user_id = $1
+    AND t.name = ANY($2::citext[])
+    GROUP BY ut.url_id
+    HAVING COUNT(*) = cardinality($2::citext[])
+))
+AND ($3::text IS NULL
+    OR r.original_url ILIKE $3::text
+    OR r.short_code ILIKE $3::text
+    OR r.title ILIKE $3::text
+    OR r.notes ILIKE $3::text)
+AND ($4::text IS NULL
+    OR ($4::text = 'active'
+        AND NOT r.disabled
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND (r.active_from IS NULL OR r.active_from <= CURRENT_TIMESTAMP)
+        AND (r.max_clicks IS NULL OR r.redirect_count < r.max_clicks))
+    OR ($4::text = 'expired'
+        AND (r.expired_at <= CURRENT_TIMESTAMP OR r.redirect_count >= COALESCE(r.max_clicks, r.redirect_count + 1)))
+    OR ($4::text = 'disabled'
+        AND r.disabled)
+    OR ($4::text = 'scheduled'
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND r.active_from > CURRENT_TIMESTAMP))
+AND ($5::boolean IS NULL OR r.is_custom = $5::boolean)
+AND ($6::timestamp IS NULL OR r.created_at >= $6::timestamp)
+AND ($7::timestamp IS NULL OR r.created_at < $7::timestamp)
+AND ($8::int IS NULL OR r.views >= $8::int)
+AND ($9::int IS NULL OR r.views <= $9::int)
+AND ($10::bigint IS NULL OR (r.original_url, r.id) < ($11::text, $10::bigint))
+ORDER BY r.original_url DESC, r.id DESC
+LIMIT $12 OFFSET $13
+`
+
+type GetURLsByUserIDOriginalURLDescParams struct {
+	UserID      int32          `json:"user_id"`
+	Tags        []string       `json:"tags"`
+	Pattern     sql.NullString `json:"pattern"`
+	Status      sql.NullString `json:"status"`
+	IsCustom    sql.NullBool   `json:"is_custom"`
+	CreatedFrom sql.NullTime   `json:"created_from"`
+	CreatedTo   sql.NullTime   `json:"created_to"`
+	MinViews    sql.NullInt32  `json:"min_views"`
+	MaxViews    sql.NullInt32  `json:"max_views"`
+	CursorID    sql.NullInt64  `json:"cursor_id"`
+	CursorText  sql.NullString `json:"cursor_text"`
+	Limit       int32          `json:"limit"`
+	Offset      int32          `json:"offset"`
+}
+
+type GetURLsByUserIDOriginalURLDescRow struct {
+	ID            int64         `json:"id"`
+	OriginalUrl   string        `json:"original_url"`
+	RawUrl        string        `json:"raw_url"`
+	ShortCode     string        `json:"short_code"`
+	Views         int32         `json:"views"`
+	IsCustom      bool          `json:"is_custom"`
+	ExpiredAt     time.Time     `json:"expired_at"`
+	MaxClicks     sql.NullInt32 `json:"max_clicks"`
+	RedirectCount int32         `json:"redirect_count"`
+	Title         string        `json:"title"`
+	Notes         string        `json:"notes"`
+	Disabled      bool          `json:"disabled"`
+	CreatedAt     time.Time     `json:"created_at"`
+	Host          string        `json:"host"`
+}
+
+func (q *Queries) GetURLsByUserIDOriginalURLDesc(ctx context.Context, arg GetURLsByUserIDOriginalURLDescParams) ([]GetURLsByUserIDOriginalURLDescRow, error) {
+	rows, err := q.db.QueryContext(ctx, getURLsByUserIDOriginalURLDesc,
+		arg.UserID,
+		pq.Array(arg.Tags),
+		arg.Pattern,
+		arg.Status,
+		arg.IsCustom,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.MinViews,
+		arg.MaxViews,
+		arg.CursorID,
+		arg.CursorText,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetURLsByUserIDOriginalURLDescRow
+	for rows.Next() {
+		var i GetURLsByUserIDOriginalURLDescRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OriginalUrl,
+			&i.RawUrl,
+			&i.ShortCode,
+			&i.Views,
+			&i.IsCustom,
+			&i.ExpiredAt,
+			&i.MaxClicks,
+			&i.RedirectCount,
+			&i.Title,
+			&i.Notes,
+			&i.Disabled,
+			&i.CreatedAt,
+			&i.Host,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getURLsByUserIDViewsAsc = `-- name: GetURLsByUserIDViewsAsc :many
+SELECT r.id, r.original_url, r.raw_url, r.short_code, r.views, r.is_custom, r.expired_at, r.max_clicks, r.redirect_count, r.title, r.notes, r.disabled, r.created_at, COALESCE(d.host, '')::text AS host
+FROM urls r
+LEFT JOIN domains d ON d.id = r.domain_id
+WHERE r.user_id = $1
+AND r.deleted_at IS NULL
+AND (cardinality($2::citext[]) = 0 OR r.id IN (
+    SELECT ut.url_id
+    FROM url_tags ut
+    JOIN tags t ON t.id = ut.tag_id
+    WHERE t.user_id = $1
+    AND t.name = ANY($2::citext[])
+    GROUP BY ut.url_id
+    HAVING COUNT(*) = cardinality($2::citext[])
+))
+AND ($3::text IS NULL
+    OR r.original_url ILIKE $3::text
+    OR r.short_code ILIKE $3::text
+    OR r.title ILIKE $3::text
+    OR r.notes ILIKE $3::text)
+AND ($4::text IS NULL
+    OR ($4::text = 'active'
+        AND NOT r.disabled
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND (r.active_from IS NULL OR r.active_from <= CURRENT_TIMESTAMP)
+        AND (r.max_clicks IS NULL OR r.redirect_count < r.max_clicks))
+    OR ($4::text = 'expired'
+        AND (r.expired_at <= CURRENT_TIMESTAMP OR r.redirect_count >= COALESCE(r.max_clicks, r.redirect_count + 1)))
+    OR ($4::text = 'disabled'
+        AND r.disabled)
+    OR ($4::text = 'scheduled'
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND r.active_from > CURRENT_TIMESTAMP))
+AND ($5::boolean IS NULL OR r.is_custom = $5::boolean)
+AND ($6::timestamp IS NULL OR r.created_at >= $6::timestamp)
+AND ($7::timestamp IS NULL OR r.created_at < $7::timestamp)
+AND ($8::int IS NULL OR r.views >= $8::int)
+AND ($9::int IS NULL OR r.views <= $9::int)
+AND ($10::bigint IS NULL OR (r.views, r.id) > ($11::int, $10::bigint))
+ORDER BY r.views, r.id
+LIMIT $12 OFFSET $13
+`
+
+type GetURLsByUserIDViewsAscParams struct {
+	UserID      int32          `json:"user_id"`
+	Tags        []string       `json:"tags"`
+	Pattern     sql.NullString `json:"pattern"`
+	Status      sql.NullString `json:"status"`
+	IsCustom    sql.NullBool   `json:"is_custom"`
+	CreatedFrom sql.NullTime   `json:"created_from"`
+	CreatedTo   sql.NullTime   `json:"created_to"`
+	MinViews    sql.NullInt32  `json:"min_views"`
+	MaxViews    sql.NullInt32  `json:"max_views"`
+	CursorID    sql.NullInt64  `json:"cursor_id"`
+	CursorViews sql.NullInt32  `json:"cursor_views"`
+	Limit       int32          `json:"limit"`
+	Offset      int32          `json:"offset"`
+}
+
+type GetURLsByUserIDViewsAscRow struct {
+	ID            int64         `json:"id"`
+	OriginalUrl   string        `json:"original_url"`
+	RawUrl        string        `json:"raw_url"`
+	ShortCode     string        `json:"short_code"`
+	Views         int32         `json:"views"`
+	IsCustom      bool          `json:"is_custom"`
+	ExpiredAt     time.Time     `json:"expired_at"`
+	MaxClicks     sql.NullInt32 `json:"max_clicks"`
+	RedirectCount int32         `json:"redirect_count"`
+	Title         string        `json:"title"`
+	Notes         string        `json:"notes"`
+	Disabled      bool          `json:"disabled"`
+	CreatedAt     time.Time     `json:"created_at"`
+	Host          string        `json:"host"`
+}
+
+func (q *Queries) GetURLsByUserIDViewsAsc(ctx context.Context, arg GetURLsByUserIDViewsAscParams) ([]GetURLsByUserIDViewsAscRow, error) {
+	rows, err := q.db.QueryContext(ctx, getURLsByUserIDViewsAsc,
+		arg.UserID,
+		pq.Array(arg.Tags),
+		arg.Pattern,
+		arg.Status,
+		arg.IsCustom,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.MinViews,
+		arg.MaxViews,
+		arg.CursorID,
+		arg.CursorViews,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetURLsByUserIDViewsAscRow
+	for rows.Next() {
+		var i GetURLsByUserIDViewsAscRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OriginalUrl,
+			&i.RawUrl,
+			&i.ShortCode,
+			&i.Views,
+			&i.IsCustom,
+			&i.ExpiredAt,
+			&i.MaxClicks,
+			&i.RedirectCount,
+			&i.Title,
+			&i.Notes,
+			&i.Disabled,
+			&i.CreatedAt,
+			&i.Host,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getURLsByUserIDViewsDesc = `-- name: GetURLsByUserIDViewsDesc :many
+SELECT r.id, r.original_url, r.raw_url, r.short_code, r.views, r.is_custom, r.expired_at, r.max_clicks, r.redirect_count, r.title, r.notes, r.disabled, r.created_at, COALESCE(d.host, '')::text AS host
+FROM urls r
+LEFT JOIN domains d ON d.id = r.domain_id
+WHERE r.user_id = $1
+AND r.deleted_at IS NULL
+AND (cardinality($2::citext[]) = 0 OR r.id IN (
+    SELECT ut.url_id
+    FROM url_tags ut
+    JOIN tags t ON t.id = ut.tag_id
+    WHERE t.user_id = $1
+    AND t.name = ANY($2::citext[])
+    GROUP BY ut.url_id
+    HAVING COUNT(*) = cardinality($2::citext[])
+))
+AND ($3::text IS NULL
+    OR r.original_url ILIKE $3::text
+    OR r.short_code ILIKE $3::text
+    OR r.title ILIKE $3::text
+    OR r.notes ILIKE $3::text)
+AND ($4::text IS NULL
+    OR ($4::text = 'active'
+        AND NOT r.disabled
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND (r.active_from IS NULL OR r.active_from <= CURRENT_TIMESTAMP)
+        AND (r.max_clicks IS NULL OR r.redirect_count < r.max_clicks))
+    OR ($4::text = 'expired'
+        AND (r.expired_at <= CURRENT_TIMESTAMP OR r.redirect_count >= COALESCE(r.max_clicks, r.redirect_count + 1)))
+    OR ($4::text = 'disabled'
+        AND r.disabled)
+    OR ($4::text = 'scheduled'
+        AND r.expired_at > CURRENT_TIMESTAMP
+        AND r.active_from > CURRENT_TIMESTAMP))
+AND ($5::boolean IS NULL OR r.is_custom = $5::boolean)
+AND ($6::timestamp IS NULL OR r.created_at >= $6::timestamp)
+AND ($7::timestamp IS NULL OR r.created_at < $7::timestamp)
+AND ($8::int IS NULL OR r.views >= $8::int)
+AND ($9::int IS NULL OR r.views <= $9::int)
+AND ($10::bigint IS NULL OR (r.views, r.id) < ($11::int, $10::bigint))
+ORDER BY r.views DESC, r.id DESC
+LIMIT $12 OFFSET $13
+`
+
+type GetURLsByUserIDViewsDescParams struct {
+	UserID      int32          `json:"user_id"`
+	Tags        []string       `json:"tags"`
+	Pattern     sql.NullString `json:"pattern"`
+	Status      sql.NullString `json:"status"`
+	IsCustom    sql.NullBool   `json:"is_custom"`
+	CreatedFrom sql.NullTime   `json:"created_from"`
+	CreatedTo   sql.NullTime   `json:"created_to"`
+	MinViews    sql.NullInt32  `json:"min_views"`
+	MaxViews    sql.NullInt32  `json:"max_views"`
+	CursorID    sql.NullInt64  `json:"cursor_id"`
+	CursorViews sql.NullInt32  `json:"cursor_views"`
+	Limit       int32          `json:"limit"`
+	Offset      int32          `json:"offset"`
+}
+
+type GetURLsByUserIDViewsDescRow struct {
+	ID            int64         `json:"id"`
+	OriginalUrl   string        `json:"original_url"`
+	RawUrl        string        `json:"raw_url"`
+	ShortCode     string        `json:"short_code"`
+	Views         int32         `json:"views"`
+	IsCustom      bool          `json:"is_custom"`
+	ExpiredAt     time.Time     `json:"expired_at"`
+	MaxClicks     sql.NullInt32 `json:"max_clicks"`
+	RedirectCount int32         `json:"redirect_count"`
+	Title         string        `json:"title"`
+	Notes         string        `json:"notes"`
+	Disabled      bool          `json:"disabled"`
+	CreatedAt     time.Time     `json:"created_at"`
+	Host          string        `json:"host"`
+}
+
+func (q *Queries) GetURLsByUserIDViewsDesc(ctx context.Context, arg GetURLsByUserIDViewsDescParams) ([]GetURLsByUserIDViewsDescRow, error) {
+	rows, err := q.db.QueryContext(ctx, getURLsByUserIDViewsDesc,
+		arg.UserID,
+		pq.Array(arg.Tags),
+		arg.Pattern,
+		arg.Status,
+		arg.IsCustom,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.MinViews,
+		arg.MaxViews,
+		arg.CursorID,
+		arg.CursorViews,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetURLsByUserIDViewsDescRow
+	for rows.Next() {
+		var i GetURLsByUserIDViewsDescRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OriginalUrl,
+			&i.RawUrl,
+			&i.ShortCode,
+			&i.Views,
+			&i.IsCustom,
+			&i.ExpiredAt,
+			&i.MaxClicks,
+			&i.RedirectCount,
+			&i.Title,
+			&i.Notes,
+			&i.Disabled,
+			&i.CreatedAt,
+			&i.Host,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getViewsByID = `-- name: GetViewsByID :one
+SELECT views FROM urls
+WHERE id = $1
+`
+
+func (q *Queries) GetViewsByID(ctx context.Context, id int64) (int32, error) {
+	row := q.db.QueryRowContext(ctx, getViewsByID, id)
+	var views int32
+	err := row.Scan(&views)
+	return views, err
+}
+
+const isShortCodeAvailable = `-- name: IsShortCodeAvailable :one
+SELECT NOT EXISTS(
+    SELECT 1 FROM urls
+    WHERE short_code = $1
+    AND domain_id IS NOT DISTINCT FROM $2
+) AS is_available
+`
+
+type IsShortCodeAvailableParams struct {
+	ShortCode string        `json:"short_code"`
+	DomainID  sql.NullInt64 `json:"domain_id"`
+}
+
+func (q *Queries) IsShortCodeAvailable(ctx context.Context, arg IsShortCodeAvailableParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isShortCodeAvailable, arg.ShortCode, arg.DomainID)
+	var is_available bool
+	err := row.Scan(&is_available)
+	return is_available, err
+}
+
+const listURLsWithoutHash = `-- name: ListURLsWithoutHash :many
+SELECT id, original_url FROM urls
+WHERE url_hash = ''
+ORDER BY id
+LIMIT $1
+`
+
+type ListURLsWithoutHashRow struct {
+	ID          int64  `json:"id"`
+	OriginalUrl string `json:"original_url"`
+}
+
+// 迁移前创建的短链接还没有url_hash，由应用使用同一规范化规则回填
+func (q *Queries) ListURLsWithoutHash(ctx context.Context, limit int32) ([]ListURLsWithoutHashRow, error) {
+	rows, err := q.db.QueryContext(ctx, listURLsWithoutHash, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListURLsWithoutHashRow{}
+	for rows.Next() {
+		var i ListURLsWithoutHashRow
+		if err := rows.Scan(&i.ID, &i.OriginalUrl); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeTrashedURLs = `-- name: PurgeTrashedURLs :many
+DELETE FROM urls
+WHERE id IN (
+    SELECT id FROM urls
+    WHERE deleted_at < $1
+    LIMIT $2
+)
+RETURNING short_code, COALESCE((SELECT host FROM domains WHERE domains.id = urls.domain_id), '')::text AS host
+`
+
+type PurgeTrashedURLsParams struct {
+	DeletedBefore sql.NullTime `json:"deleted_before"`
+	RowLimit      int32        `json:"row_limit"`
+}
+
+type PurgeTrashedURLsRow struct {
+	ShortCode string `json:"short_code"`
+	Host      string `json:"host"`
+}
+
+func (q *Queries) PurgeTrashedURLs(ctx context.Context, arg PurgeTrashedURLsParams) ([]PurgeTrashedURLsRow, error) {
+	rows, err := q.db.QueryContext(ctx, purgeTrashedURLs, arg.DeletedBefore, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PurgeTrashedURLsRow
+	for rows.Next() {
+		var i PurgeTrashedURLsRow
+		if err := rows.Scan(&i.ShortCode, &i.Host); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const restoreURLByID = `-- name: RestoreURLByID :exec
+UPDATE urls
+SET deleted_at = NULL
+WHERE id = $1
+`
+
+func (q *Queries) RestoreURLByID(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, restoreURLByID, id)
+	return err
+}
+
+const setURLsDisabled = `-- name: SetURLsDisabled :exec
+UPDATE urls
+SET disabled = $1
+WHERE id = ANY($2::bigint[])
+`
+
+type SetURLsDisabledParams struct {
+	Disabled bool    `json:"disabled"`
+	Ids      []int64 `json:"ids"`
+}
+
+func (q *Queries) SetURLsDisabled(ctx context.Context, arg SetURLsDisabledParams) error {
+	_, err := q.db.ExecContext(ctx, setURLsDisabled, arg.Disabled, pq.Array(arg.Ids))
+	return err
+}
+
+const setURLsExpiry = `-- name: SetURLsExpiry :exec
+UPDATE urls
+SET expired_at = $1
+WHERE id = ANY($2::bigint[])
+`
+
+type SetURLsExpiryParams struct {
+	ExpiredAt time.Time `json:"expired_at"`
+	Ids       []int64   `json:"ids"`
+}
+
+func (q *Queries) SetURLsExpiry(ctx context.Context, arg SetURLsExpiryParams) error {
+	_, err := q.db.ExecContext(ctx, setURLsExpiry, arg.ExpiredAt, pq.Array(arg.Ids))
+	return err
+}
+
+const trashURLByID = `-- name: TrashURLByID :exec
+UPDATE urls
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1
+AND deleted_at IS NULL
+`
+
+func (q *Queries) TrashURLByID(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, trashURLByID, id)
+	return err
+}
+
+const trashURLsByIDs = `-- name: TrashURLsByIDs :exec
+UPDATE urls
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = ANY($1::bigint[])
+AND deleted_at IS NULL
+`
+
+func (q *Queries) TrashURLsByIDs(ctx context.Context, ids []int64) error {
+	_, err := q.db.ExecContext(ctx, trashURLsByIDs, pq.Array(ids))
+	return err
+}
+
+const updateURLContentByID = `-- name: UpdateURLContentByID :exec
+UPDATE urls
+SET original_url = $1,
+    raw_url = COALESCE($2, raw_url),
+    title = $3,
+    notes = $4,
+    url_hash = $5
+WHERE id = $6
+`
+
+type UpdateURLContentByIDParams struct {
+	OriginalUrl string         `json:"original_url"`
+	RawUrl      sql.NullString `json:"raw_url"`
+	Title       string         `json:"title"`
+	Notes       string         `json:"notes"`
+	UrlHash     string         `json:"url_hash"`
+	ID          int64          `json:"id"`
+}
+
+func (q *Queries) UpdateURLContentByID(ctx context.Context, arg UpdateURLContentByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateURLContentByID,
+		arg.OriginalUrl,
+		arg.RawUrl,
+		arg.Title,
+		arg.Notes,
+		arg.UrlHash,
+		arg.ID,
+	)
+	return err
+}
+
+const updateURLHash = `-- name: UpdateURLHash :exec
+UPDATE urls SET url_hash = $2
+WHERE id = $1
+`
+
+type UpdateURLHashParams struct {
+	ID      int64  `json:"id"`
+	UrlHash string `json:"url_hash"`
+}
+
+func (q *Queries) UpdateURLHash(ctx context.Context, arg UpdateURLHashParams) error {
+	_, err := q.db.ExecContext(ctx, updateURLHash, arg.ID, arg.UrlHash)
+	return err
+}
+
+const updateURLSettingsByID = `-- name: UpdateURLSettingsByID :exec
+UPDATE urls
+SET expired_at = COALESCE($1, expired_at),
+    active_from = CASE WHEN $2::boolean THEN NULL ELSE COALESCE($3, active_from) END,
+    redirect_type = COALESCE($4, redirect_type),
+    always_preview = COALESCE($5, always_preview)
+WHERE id = $6
+`
+
+type UpdateURLSettingsByIDParams struct {
+	ExpiredAt       sql.NullTime   `json:"expired_at"`
+	ClearActiveFrom bool           `json:"clear_active_from"`
+	ActiveFrom      sql.NullTime   `json:"active_from"`
+	RedirectType    sql.NullString `json:"redirect_type"`
+	AlwaysPreview   sql.NullBool   `json:"always_preview"`
+	ID              int64          `json:"id"`
+}
+
+func (q *Queries) UpdateURLSettingsByID(ctx context.Context, arg UpdateURLSettingsByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateURLSettingsByID,
+		arg.ExpiredAt,
+		arg.ClearActiveFrom,
+		arg.ActiveFrom,
+		arg.RedirectType,
+		arg.AlwaysPreview,
+		arg.ID,
+	)
+	return err
+}
+
+const updateViewsByHostAndShortCode = `-- name: UpdateViewsByHostAndShortCode :exec
+UPDATE urls
+SET views = views + $1
+WHERE short_code = $2
+AND domain_id IS NOT DISTINCT FROM (
+    SELECT id FROM domains
+    WHERE domains.host = $3
+    AND verified_at IS NOT NULL
+)
 `
 
-type UpdateViewsByShortCodeParams struct {
+type UpdateViewsByHostAndShortCodeParams struct {
 	Views     int32  `json:"views"`
 	ShortCode string `json:"short_code"`
+	Host      string `json:"host"`
 }
 
-func (q *Queries) UpdateViewsByShortCode(ctx context.Context, arg UpdateViewsByShortCodeParams) error {
-	_, err := q.db.ExecContext(ctx, updateViewsByShortCode, arg.Views, arg.ShortCode)
+func (q *Queries) UpdateViewsByHostAndShortCode(ctx context.Context, arg UpdateViewsByHostAndShortCodeParams) error {
+	_, err := q.db.ExecContext(ctx, updateViewsByHostAndShortCode, arg.Views, arg.ShortCode, arg.Host)
 	return err
 }
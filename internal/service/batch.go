@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/aeilang/urlshortener/internal/repo"
+)
+
+// BatchURLs 对用户选中的多个短链接执行同一操作，整个操作用一条语句完成，
+// 不存在或不属于该用户的短链接返回not_found。受影响的缓存通过pipeline一次删除
+func (s *URLService) BatchURLs(ctx context.Context, req model.BatchURLsRequest) (*model.BatchURLsResponse, error) {
+	if err := checkBatch(req); err != nil {
+		return nil, err
+	}
+
+	if req.Action == model.BatchAddTag || req.Action == model.BatchRemoveTag {
+		owned, err := s.querier.IsTagOwnedByUser(ctx, repo.IsTagOwnedByUserParams{
+			ID:     req.TagID,
+			UserID: int32(req.UserID),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !owned {
+			return nil, model.ErrTagNotFound
+		}
+	}
+
+	codes := make([]string, 0, len(req.Codes))
+	seen := make(map[string]bool, len(req.Codes))
+	for _, code := range req.Codes {
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+
+	rows, err := s.querier.GetURLsByShortCodesAndUserID(ctx, repo.GetURLsByShortCodesAndUserIDParams{
+		ShortCodes: codes,
+		UserID:     int32(req.UserID),
+		Host:       req.Domain,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make(map[string]urlRef, len(rows))
+	ids := make([]int64, 0, len(rows))
+	keys := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ref := urlRef{id: row.ID, domain: row.Host, shortCode: row.ShortCode}
+		refs[row.ShortCode] = ref
+		ids = append(ids, ref.id)
+		keys = append(keys, ref.key())
+	}
+
+	if len(ids) > 0 {
+		if err := s.applyBatch(ctx, req, ids); err != nil {
+			return nil, err
+		}
+
+		if err := s.cache.DelURLCount(ctx, req.UserID); err != nil {
+			return nil, err
+		}
+
+		// 标签不影响跳转，不需要删除短链接缓存
+		if req.Action != model.BatchAddTag && req.Action != model.BatchRemoveTag {
+			if err := s.cache.DelURLs(ctx, keys); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	resp := &model.BatchURLsResponse{Items: make([]model.BatchURLResult, len(codes))}
+	for i, code := range codes {
+		resp.Items[i].Code = code
+		if _, ok := refs[code]; ok {
+			resp.Items[i].Status = model.BatchOK
+			resp.Succeeded++
+		} else {
+			resp.Items[i].Status = model.BatchNotFound
+			resp.Failed++
+		}
+	}
+
+	return resp, nil
+}
+
+// checkBatch 检查操作需要的参数是否齐全
+func checkBatch(req model.BatchURLsRequest) error {
+	switch req.Action {
+	case model.BatchExtend:
+		if req.Duration == 0 {
+			return model.ErrInvalidBatch
+		}
+	case model.BatchSetExpiry:
+		if req.ExpiredAt == nil {
+			return model.ErrInvalidBatch
+		}
+	case model.BatchAddTag, model.BatchRemoveTag:
+		if req.TagID == 0 {
+			return model.ErrInvalidBatch
+		}
+	}
+
+	return nil
+}
+
+func (s *URLService) applyBatch(ctx context.Context, req model.BatchURLsRequest, ids []int64) error {
+	switch req.Action {
+	case model.BatchDelete:
+		return s.querier.TrashURLsByIDs(ctx, ids)
+	case model.BatchExtend:
+		return s.querier.ExtendURLsExpiry(ctx, repo.ExtendURLsExpiryParams{
+			Seconds: int64(time.Duration(req.Duration) * time.Hour / time.Second),
+			Ids:     ids,
+		})
+	case model.BatchSetExpiry:
+		return s.querier.SetURLsExpiry(ctx, repo.SetURLsExpiryParams{
+			ExpiredAt: req.ExpiredAt.UTC(),
+			Ids:       ids,
+		})
+	case model.BatchAddTag:
+		tagIDs := make([]int64, len(ids))
+		for i := range tagIDs {
+			tagIDs[i] = req.TagID
+		}
+		return s.querier.AttachTags(ctx, repo.AttachTagsParams{
+			UrlIds: ids,
+			TagIds: tagIDs,
+		})
+	case model.BatchRemoveTag:
+		return s.querier.DetachTagFromURLs(ctx, repo.DetachTagFromURLsParams{
+			TagID:  req.TagID,
+			UrlIds: ids,
+		})
+	case model.BatchDisable, model.BatchEnable:
+		return s.querier.SetURLsDisabled(ctx, repo.SetURLsDisabledParams{
+			Disabled: req.Action == model.BatchDisable,
+			Ids:      ids,
+		})
+	}
+
+	return model.ErrInvalidBatch
+}
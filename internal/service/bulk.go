@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/aeilang/urlshortener/internal/repo"
+)
+
+const (
+	bulkChunkSize  = 500 // 每个事务创建的短链接数量
+	bulkMaxRetries = 5   // 生成的短链接代码冲突时的重试次数
+)
+
+// BulkCreateURLs 批量创建短链接，每bulkChunkSize行在一个事务中批量插入，
+// 某一批失败时只影响该批的行。新建的短链接不写入缓存，第一次访问时再缓存
+func (s *URLService) BulkCreateURLs(ctx context.Context, req model.BulkCreateURLsRequest) (*model.BulkCreateURLsResponse, error) {
+	var domainID sql.NullInt64
+	var domain string
+	if req.Domain != "" {
+		var err error
+		domainID, domain, err = s.verifiedDomain(ctx, req.Domain, req.UserID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]model.BulkURLResult, len(req.Items))
+	pending := make([]int, 0, len(req.Items))
+	urls := make([]string, len(req.Items))
+
+	// 同一次上传中重复的自定义代码只有第一个可能创建成功
+	seen := make(map[string]bool)
+	for i, item := range req.Items {
+		results[i].Row = item.Row
+		canonical, err := s.urlNormalizer.Normalize(item.OriginalURL)
+		if err != nil {
+			results[i].Status = model.BulkInvalidURL
+			results[i].Error = model.ErrInvalidURL.Error()
+			continue
+		}
+		urls[i] = canonical
+		if item.CustomCode != "" {
+			if seen[item.CustomCode] {
+				results[i].Status = model.BulkCodeTaken
+				continue
+			}
+			seen[item.CustomCode] = true
+		}
+		pending = append(pending, i)
+	}
+
+	for start := 0; start < len(pending); start += bulkChunkSize {
+		end := min(start+bulkChunkSize, len(pending))
+		chunk := pending[start:end]
+
+		if err := s.execTx(ctx, func(q *repo.Queries) error {
+			return s.createChunk(ctx, q, req, domainID, domain, urls, chunk, results)
+		}); err != nil {
+			for _, i := range chunk {
+				results[i] = model.BulkURLResult{Row: req.Items[i].Row, Status: model.BulkFailed, Error: err.Error()}
+			}
+		}
+	}
+
+	resp := model.BulkCreateURLsResponse{Items: results}
+	for _, result := range results {
+		if result.Status == model.BulkCreated {
+			resp.Created++
+		} else {
+			resp.Failed++
+		}
+	}
+
+	if resp.Created > 0 {
+		if err := s.cache.DelURLCount(ctx, req.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &resp, nil
+}
+
+// createChunk 在事务中插入一批短链接，urls为规范化后的原始URL。代码冲突的行不会插入，生成的代码冲突时换一个重试
+func (s *URLService) createChunk(ctx context.Context, q *repo.Queries, req model.BulkCreateURLsRequest, domainID sql.NullInt64, domain string, urls []string, chunk []int, results []model.BulkURLResult) error {
+	codes := make(map[int]string, len(chunk))
+	for _, i := range chunk {
+		codes[i] = req.Items[i].CustomCode
+	}
+
+	var ids []int64
+	created := make([]int, 0, len(chunk))
+
+	for attempt := 0; len(chunk) > 0; attempt++ {
+		if attempt > bulkMaxRetries {
+			for _, i := range chunk {
+				results[i].Status = model.BulkFailed
+				results[i].Error = "重试过多"
+			}
+			break
+		}
+
+		// 生成的代码在本批中也不能重复，否则无法根据返回的代码找到对应的行
+		used := make(map[string]bool, len(chunk))
+		for _, i := range chunk {
+			if req.Items[i].CustomCode == "" {
+				code := s.shortCodeGenerator.GenerateShortCode()
+				for used[code] {
+					code = s.shortCodeGenerator.GenerateShortCode()
+				}
+				codes[i] = code
+			}
+			used[codes[i]] = true
+		}
+
+		params := repo.BulkCreateURLsParams{
+			UserID:   int32(req.UserID),
+			DomainID: domainID,
+		}
+		for _, i := range chunk {
+			item := req.Items[i]
+
+			ttl := s.urlDefaultDuration
+			if item.Duration != nil {
+				ttl = time.Hour * time.Duration(*item.Duration)
+			}
+
+			params.OriginalUrls = append(params.OriginalUrls, urls[i])
+			params.ShortCodes = append(params.ShortCodes, codes[i])
+			params.IsCustoms = append(params.IsCustoms, item.CustomCode != "")
+			params.Ttls = append(params.Ttls, int64(ttl.Seconds()))
+			params.Titles = append(params.Titles, item.Title)
+			params.Notes = append(params.Notes, item.Notes)
+			params.UrlHashes = append(params.UrlHashes, urlHash(urls[i]))
+			params.RawUrls = append(params.RawUrls, item.OriginalURL)
+		}
+
+		rows, err := q.BulkCreateURLs(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		inserted := make(map[string]int64, len(rows))
+		for _, row := range rows {
+			inserted[row.ShortCode] = row.ID
+		}
+
+		var retry []int
+		for _, i := range chunk {
+			id, ok := inserted[codes[i]]
+			switch {
+			case ok:
+				results[i].Status = model.BulkCreated
+				results[i].ShortURL = s.shortURL(domain, codes[i])
+				ids = append(ids, id)
+				created = append(created, i)
+			case req.Items[i].CustomCode != "":
+				results[i].Status = model.BulkCodeTaken
+			default:
+				retry = append(retry, i)
+			}
+		}
+		chunk = retry
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := q.CreateInitialURLRevisions(ctx, ids); err != nil {
+		return err
+	}
+
+	return attachBulkTags(ctx, q, req, created, ids)
+}
+
+// attachBulkTags 为新建的短链接添加标签，不存在的标签会自动创建
+func attachBulkTags(ctx context.Context, q *repo.Queries, req model.BulkCreateURLsRequest, created []int, ids []int64) error {
+	var names []string
+	for _, i := range created {
+		names = append(names, req.Items[i].Tags...)
+	}
+	names = uniqueTags(names)
+	if len(names) == 0 {
+		return nil
+	}
+
+	if err := q.CreateTagsIfNotExist(ctx, repo.CreateTagsIfNotExistParams{
+		UserID: int32(req.UserID),
+		Names:  names,
+	}); err != nil {
+		return err
+	}
+
+	tags, err := q.ListTagsByNames(ctx, repo.ListTagsByNamesParams{
+		UserID: int32(req.UserID),
+		Names:  names,
+	})
+	if err != nil {
+		return err
+	}
+
+	tagIDs := make(map[string]int64, len(tags))
+	for _, tag := range tags {
+		tagIDs[strings.ToLower(tag.Name)] = tag.ID
+	}
+
+	var params repo.AttachTagsParams
+	for n, i := range created {
+		for _, name := range uniqueTags(req.Items[i].Tags) {
+			if id, ok := tagIDs[strings.ToLower(name)]; ok {
+				params.UrlIds = append(params.UrlIds, ids[n])
+				params.TagIds = append(params.TagIds, id)
+			}
+		}
+	}
+
+	return q.AttachTags(ctx, params)
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/aeilang/urlshortener/internal/repo"
+)
+
+// urlCursor 短链接列表的分页游标，记录上一页最后一条的排序值和ID
+type urlCursor struct {
+	Sort  string    `json:"s"`
+	Desc  bool      `json:"d"`
+	ID    int64     `json:"i"`
+	Time  time.Time `json:"t,omitempty"`
+	Views int32     `json:"v,omitempty"`
+	Text  string    `json:"x,omitempty"`
+}
+
+func newURLCursor(sort string, desc bool, row urlListRow) urlCursor {
+	c := urlCursor{Sort: sort, Desc: desc, ID: row.ID}
+	switch sort {
+	case model.URLSortExpiredAt:
+		c.Time = row.ExpiredAt
+	case model.URLSortViews:
+		c.Views = row.Views
+	case model.URLSortOriginalURL:
+		c.Text = row.OriginalUrl
+	default:
+		c.Time = row.CreatedAt
+	}
+	return c
+}
+
+// encode 编码为不透明的字符串，客户端原样传回即可
+func (c urlCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeURLCursor 解析游标，排序方式与本次请求不一致时返回 model.ErrInvalidCursor
+func decodeURLCursor(s, sort string, desc bool) (urlCursor, error) {
+	var c urlCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, model.ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, model.ErrInvalidCursor
+	}
+	if c.Sort != sort || c.Desc != desc || c.ID == 0 {
+		return c, model.ErrInvalidCursor
+	}
+
+	return c, nil
+}
+
+// urlListRow 各排序方式的查询返回的列相同，转换为同一类型后处理
+type urlListRow repo.GetURLsByUserIDCreatedAtAscRow
+
+type urlListRowTypes interface {
+	repo.GetURLsByUserIDCreatedAtAscRow | repo.GetURLsByUserIDCreatedAtDescRow |
+		repo.GetURLsByUserIDExpiredAtAscRow | repo.GetURLsByUserIDExpiredAtDescRow |
+		repo.GetURLsByUserIDOriginalURLAscRow | repo.GetURLsByUserIDOriginalURLDescRow |
+		repo.GetURLsByUserIDViewsAscRow | repo.GetURLsByUserIDViewsDescRow
+}
+
+func toURLListRows[R urlListRowTypes](rows []R, err error) ([]urlListRow, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]urlListRow, len(rows))
+	for i, row := range rows {
+		items[i] = urlListRow(row)
+	}
+	return items, nil
+}
+
+// urlPage 查询一页短链接的条件，cursor为空时从第一条开始
+type urlPage struct {
+	filter repo.CountURLsByUserIDParams
+	sort   string
+	desc   bool
+	cursor *urlCursor
+	limit  int32
+	offset int32
+}
+
+// listURLs 每种排序方式和方向各用一个查询，游标条件和ORDER BY都是固定的列，才能使用索引
+func (s *URLService) listURLs(ctx context.Context, page urlPage) ([]urlListRow, error) {
+	f := page.filter
+
+	var cursorID sql.NullInt64
+	var cursor urlCursor
+	if page.cursor != nil {
+		cursor = *page.cursor
+		cursorID = sql.NullInt64{Int64: cursor.ID, Valid: true}
+	}
+
+	switch page.sort {
+	case model.URLSortViews:
+		params := repo.GetURLsByUserIDViewsAscParams{
+			UserID:      f.UserID,
+			Tags:        f.Tags,
+			Pattern:     f.Pattern,
+			Status:      f.Status,
+			IsCustom:    f.IsCustom,
+			CreatedFrom: f.CreatedFrom,
+			CreatedTo:   f.CreatedTo,
+			MinViews:    f.MinViews,
+			MaxViews:    f.MaxViews,
+			CursorID:    cursorID,
+			CursorViews: sql.NullInt32{Int32: cursor.Views, Valid: cursorID.Valid},
+			Limit:       page.limit,
+			Offset:      page.offset,
+		}
+		if page.desc {
+			return toURLListRows(s.querier.GetURLsByUserIDViewsDesc(ctx, repo.GetURLsByUserIDViewsDescParams(params)))
+		}
+		return toURLListRows(s.querier.GetURLsByUserIDViewsAsc(ctx, params))
+
+	case model.URLSortOriginalURL:
+		params := repo.GetURLsByUserIDOriginalURLAscParams{
+			UserID:      f.UserID,
+			Tags:        f.Tags,
+			Pattern:     f.Pattern,
+			Status:      f.Status,
+			IsCustom:    f.IsCustom,
+			CreatedFrom: f.CreatedFrom,
+			CreatedTo:   f.CreatedTo,
+			MinViews:    f.MinViews,
+			MaxViews:    f.MaxViews,
+			CursorID:    cursorID,
+			CursorText:  sql.NullString{String: cursor.Text, Valid: cursorID.Valid},
+			Limit:       page.limit,
+			Offset:      page.offset,
+		}
+		if page.desc {
+			return toURLListRows(s.querier.GetURLsByUserIDOriginalURLDesc(ctx, repo.GetURLsByUserIDOriginalURLDescParams(params)))
+		}
+		return toURLListRows(s.querier.GetURLsByUserIDOriginalURLAsc(ctx, params))
+	}
+
+	// 按时间排序的查询参数相同
+	params := repo.GetURLsByUserIDCreatedAtAscParams{
+		UserID:      f.UserID,
+		Tags:        f.Tags,
+		Pattern:     f.Pattern,
+		Status:      f.Status,
+		IsCustom:    f.IsCustom,
+		CreatedFrom: f.CreatedFrom,
+		CreatedTo:   f.CreatedTo,
+		MinViews:    f.MinViews,
+		MaxViews:    f.MaxViews,
+		CursorID:    cursorID,
+		CursorTime:  sql.NullTime{Time: cursor.Time, Valid: cursorID.Valid},
+		Limit:       page.limit,
+		Offset:      page.offset,
+	}
+
+	switch {
+	case page.sort == model.URLSortExpiredAt && page.desc:
+		return toURLListRows(s.querier.GetURLsByUserIDExpiredAtDesc(ctx, repo.GetURLsByUserIDExpiredAtDescParams(params)))
+	case page.sort == model.URLSortExpiredAt:
+		return toURLListRows(s.querier.GetURLsByUserIDExpiredAtAsc(ctx, repo.GetURLsByUserIDExpiredAtAscParams(params)))
+	case page.desc:
+		return toURLListRows(s.querier.GetURLsByUserIDCreatedAtDesc(ctx, repo.GetURLsByUserIDCreatedAtDescParams(params)))
+	default:
+		return toURLListRows(s.querier.GetURLsByUserIDCreatedAtAsc(ctx, params))
+	}
+}
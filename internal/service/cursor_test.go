@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/aeilang/urlshortener/internal/repo"
+)
+
+func TestURLCursorRoundTrip(t *testing.T) {
+	row := urlListRow{
+		ID:          42,
+		OriginalUrl: "https://example.com/",
+		Views:       7,
+		ExpiredAt:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		CreatedAt:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name string
+		sort string
+		desc bool
+		want urlCursor
+	}{
+		{name: "created_at", sort: model.URLSortCreatedAt, desc: true, want: urlCursor{Sort: model.URLSortCreatedAt, Desc: true, ID: 42, Time: row.CreatedAt}},
+		{name: "expired_at", sort: model.URLSortExpiredAt, want: urlCursor{Sort: model.URLSortExpiredAt, ID: 42, Time: row.ExpiredAt}},
+		{name: "views", sort: model.URLSortViews, desc: true, want: urlCursor{Sort: model.URLSortViews, Desc: true, ID: 42, Views: 7}},
+		{name: "original_url", sort: model.URLSortOriginalURL, want: urlCursor{Sort: model.URLSortOriginalURL, ID: 42, Text: "https://example.com/"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newURLCursor(tt.sort, tt.desc, row).encode()
+
+			got, err := decodeURLCursor(s, tt.sort, tt.desc)
+			if err != nil {
+				t.Fatalf("decodeURLCursor() error = %v", err)
+			}
+			if !got.Time.Equal(tt.want.Time) {
+				t.Errorf("decodeURLCursor().Time = %v, want %v", got.Time, tt.want.Time)
+			}
+			got.Time, tt.want.Time = time.Time{}, time.Time{}
+			if got != tt.want {
+				t.Errorf("decodeURLCursor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeURLCursorInvalid(t *testing.T) {
+	valid := newURLCursor(model.URLSortViews, true, urlListRow{ID: 1, Views: 3}).encode()
+
+	tests := []struct {
+		name   string
+		cursor string
+		sort   string
+		desc   bool
+	}{
+		{name: "not base64", cursor: "!!!", sort: model.URLSortViews, desc: true},
+		{name: "not json", cursor: "bm90IGpzb24", sort: model.URLSortViews, desc: true},
+		{name: "missing id", cursor: urlCursor{Sort: model.URLSortViews, Desc: true}.encode(), sort: model.URLSortViews, desc: true},
+		{name: "different sort", cursor: valid, sort: model.URLSortCreatedAt, desc: true},
+		{name: "different order", cursor: valid, sort: model.URLSortViews, desc: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeURLCursor(tt.cursor, tt.sort, tt.desc); !errors.Is(err, model.ErrInvalidCursor) {
+				t.Errorf("decodeURLCursor() error = %v, want %v", err, model.ErrInvalidCursor)
+			}
+		})
+	}
+}
+
+func TestListURLsQuery(t *testing.T) {
+	tests := []struct {
+		sort string
+		desc bool
+		want string
+	}{
+		{sort: model.URLSortCreatedAt, desc: false, want: "GetURLsByUserIDCreatedAtAsc"},
+		{sort: model.URLSortCreatedAt, desc: true, want: "GetURLsByUserIDCreatedAtDesc"},
+		{sort: model.URLSortExpiredAt, desc: false, want: "GetURLsByUserIDExpiredAtAsc"},
+		{sort: model.URLSortExpiredAt, desc: true, want: "GetURLsByUserIDExpiredAtDesc"},
+		{sort: model.URLSortOriginalURL, desc: false, want: "GetURLsByUserIDOriginalURLAsc"},
+		{sort: model.URLSortOriginalURL, desc: true, want: "GetURLsByUserIDOriginalURLDesc"},
+		{sort: model.URLSortViews, desc: false, want: "GetURLsByUserIDViewsAsc"},
+		{sort: model.URLSortViews, desc: true, want: "GetURLsByUserIDViewsDesc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			querier := &fakeQuerier{}
+			s := &URLService{querier: querier}
+
+			if _, err := s.listURLs(context.Background(), urlPage{sort: tt.sort, desc: tt.desc, limit: 11}); err != nil {
+				t.Fatalf("listURLs() error = %v", err)
+			}
+			if querier.listQuery != tt.want {
+				t.Errorf("listURLs() used %s, want %s", querier.listQuery, tt.want)
+			}
+		})
+	}
+}
+
+func TestListURLsCursorParams(t *testing.T) {
+	querier := &fakeQuerier{}
+	s := &URLService{querier: querier}
+	cursor := &urlCursor{Sort: model.URLSortViews, Desc: true, ID: 9, Views: 3}
+
+	if _, err := s.listURLs(context.Background(), urlPage{sort: model.URLSortViews, desc: true, cursor: cursor, limit: 11}); err != nil {
+		t.Fatalf("listURLs() error = %v", err)
+	}
+
+	params := querier.listParams.(repo.GetURLsByUserIDViewsDescParams)
+	if params.CursorID.Int64 != 9 || !params.CursorID.Valid || params.CursorViews.Int32 != 3 || !params.CursorViews.Valid {
+		t.Errorf("cursor params = %+v, %+v, want 9, 3", params.CursorID, params.CursorViews)
+	}
+}
+
+func TestGetURLsPage(t *testing.T) {
+	querier := &fakeQuerier{}
+	for i := 1; i <= 3; i++ {
+		querier.listRows = append(querier.listRows, urlListRow{
+			ID:        int64(i),
+			ShortCode: string(rune('a' + i)),
+			Views:     10,
+			CreatedAt: time.Date(2024, 1, i, 0, 0, 0, 0, time.UTC),
+		})
+	}
+	querier.listRows[1].Host = "go.example.com"
+
+	cache := newFakeCache()
+	cache.views["b"] = 2
+	cache.views["go.example.com/c"] = 5
+	cache.visitors["go.example.com/c"] = 4
+	s := &URLService{querier: querier, cache: cache, baseURL: "https://s.example", baseScheme: "https"}
+
+	resp, err := s.GetURLs(context.Background(), model.GetURLsRequest{Size: 2, UserID: 1})
+	if err != nil {
+		t.Fatalf("GetURLs() error = %v", err)
+	}
+
+	if len(resp.Items) != 2 {
+		t.Fatalf("len(Items) = %d, want 2", len(resp.Items))
+	}
+	if resp.Items[0].Views != 12 || resp.Items[1].Views != 15 || resp.Items[1].UniqueVisitors != 4 {
+		t.Errorf("Items = %+v, want views 12 and 15 and 4 visitors", resp.Items)
+	}
+	if resp.Items[1].ShortURL != "https://go.example.com/c" {
+		t.Errorf("ShortURL = %q, want %q", resp.Items[1].ShortURL, "https://go.example.com/c")
+	}
+	if cache.getViewsCalls != 1 || cache.visitorCalls != 1 {
+		t.Errorf("cache calls = %d views, %d visitors, want 1 each", cache.getViewsCalls, cache.visitorCalls)
+	}
+
+	cursor, err := decodeURLCursor(resp.NextCursor, model.URLSortCreatedAt, true)
+	if err != nil {
+		t.Fatalf("decodeURLCursor() error = %v", err)
+	}
+	if cursor.ID != 2 {
+		t.Errorf("NextCursor.ID = %d, want 2", cursor.ID)
+	}
+}
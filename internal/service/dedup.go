@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"net/url"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/aeilang/urlshortener/internal/repo"
+	"github.com/aeilang/urlshortener/pkg/urlnorm"
+)
+
+// urlHash 规范化后的原始URL的SHA-256，用于查找同一用户重复的目标地址
+func urlHash(canonicalURL string) string {
+	sum := sha256.Sum256([]byte(canonicalURL))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashBackfillBatchSize 每次回填url_hash的短链接数量
+const hashBackfillBatchSize = 500
+
+// BackfillURLHashes 为迁移前创建的短链接计算url_hash，使用与新建短链接相同的规范化规则，
+// 无法规范化的URL按原样计算，这些短链接不会被复用。返回回填的数量
+func (s *URLService) BackfillURLHashes(ctx context.Context) (int, error) {
+	filled := 0
+
+	for {
+		rows, err := s.querier.ListURLsWithoutHash(ctx, hashBackfillBatchSize)
+		if err != nil {
+			return filled, err
+		}
+
+		for _, row := range rows {
+			canonicalURL, err := s.urlNormalizer.Normalize(row.OriginalUrl)
+			if err != nil {
+				canonicalURL = row.OriginalUrl
+			}
+
+			if err := s.querier.UpdateURLHash(ctx, repo.UpdateURLHashParams{
+				ID:      row.ID,
+				UrlHash: urlHash(canonicalURL),
+			}); err != nil {
+				return filled, err
+			}
+		}
+
+		filled += len(rows)
+		if len(rows) < hashBackfillBatchSize {
+			return filled, nil
+		}
+	}
+}
+
+// urlWarning 目标地址的域名混用多种文字时提醒用户，可能是仿冒的域名
+func urlWarning(canonicalURL string) string {
+	u, err := url.Parse(canonicalURL)
+	if err != nil {
+		return ""
+	}
+	if urlnorm.IsMixedScript(u.Hostname()) {
+		return model.WarningMixedScript
+	}
+	return ""
+}
+
+// canDedup 只有不带访问限制的普通短链接才复用已有的短链接。
+// 指定了有效期、标题或备注时复用会丢失这些设置，总是创建新的短链接
+func canDedup(req model.CreateURLRequest) bool {
+	return !req.ForceNew &&
+		req.CustomCode == "" &&
+		req.Password == "" &&
+		req.MaxClicks == nil &&
+		req.ActiveFrom == nil &&
+		req.Duration == nil &&
+		req.Title == "" &&
+		req.Notes == ""
+}
+
+// findDuplicate 查找用户在同一域名下已有的、目标地址和跳转方式都相同且没有定向规则和A/B版本的有效短链接，
+// 没有时返回空字符串
+func (s *URLService) findDuplicate(ctx context.Context, req model.CreateURLRequest, domainID sql.NullInt64, hash string) (string, error) {
+	row, err := s.querier.GetActiveURLByHash(ctx, repo.GetActiveURLByHashParams{
+		UserID:        int32(req.UserID),
+		UrlHash:       hash,
+		DomainID:      domainID,
+		RedirectType:  req.RedirectType,
+		AlwaysPreview: req.AlwaysPreview,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return s.shortURL(row.Host, row.ShortCode), nil
+}
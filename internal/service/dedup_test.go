@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/aeilang/urlshortener/internal/repo"
+)
+
+func TestCanDedup(t *testing.T) {
+	one := 1
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		req  model.CreateURLRequest
+		want bool
+	}{
+		{name: "plain", req: model.CreateURLRequest{OriginalURL: "https://example.com/"}, want: true},
+		{name: "redirect type and preview", req: model.CreateURLRequest{OriginalURL: "https://example.com/", RedirectType: "301", AlwaysPreview: true, Domain: "go.example.com"}, want: true},
+		{name: "force new", req: model.CreateURLRequest{ForceNew: true}, want: false},
+		{name: "custom code", req: model.CreateURLRequest{CustomCode: "abcd"}, want: false},
+		{name: "password", req: model.CreateURLRequest{Password: "secret"}, want: false},
+		{name: "max clicks", req: model.CreateURLRequest{MaxClicks: &one}, want: false},
+		{name: "active from", req: model.CreateURLRequest{ActiveFrom: &now}, want: false},
+		{name: "duration", req: model.CreateURLRequest{Duration: &one}, want: false},
+		{name: "title", req: model.CreateURLRequest{Title: "t"}, want: false},
+		{name: "notes", req: model.CreateURLRequest{Notes: "n"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canDedup(tt.req); got != tt.want {
+				t.Errorf("canDedup() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackfillURLHashes(t *testing.T) {
+	tests := []struct {
+		name        string
+		rows        int
+		wantBatches int
+	}{
+		{name: "nothing to fill", rows: 0, wantBatches: 1},
+		{name: "single batch", rows: 3, wantBatches: 1},
+		{name: "several batches", rows: hashBackfillBatchSize + 1, wantBatches: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := &fakeQuerier{}
+			for i := 1; i <= tt.rows; i++ {
+				querier.unhashed = append(querier.unhashed, repo.ListURLsWithoutHashRow{ID: int64(i), OriginalUrl: fmt.Sprintf("https://Example.com/%d", i)})
+			}
+			s := &URLService{querier: querier, urlNormalizer: fakeNormalizer{}}
+
+			filled, err := s.BackfillURLHashes(context.Background())
+			if err != nil {
+				t.Fatalf("BackfillURLHashes() error = %v", err)
+			}
+			if filled != tt.rows || len(querier.hashes) != tt.rows {
+				t.Errorf("BackfillURLHashes() = %d, %d hashes, want %d", filled, len(querier.hashes), tt.rows)
+			}
+			if querier.hashBatches != tt.wantBatches {
+				t.Errorf("ListURLsWithoutHash called %d times, want %d", querier.hashBatches, tt.wantBatches)
+			}
+		})
+	}
+}
+
+func TestBackfillURLHashesNormalizes(t *testing.T) {
+	querier := &fakeQuerier{unhashed: []repo.ListURLsWithoutHashRow{
+		{ID: 1, OriginalUrl: "https://Example.com/A"},
+		{ID: 2, OriginalUrl: "https://example.com/a b"},
+	}}
+	s := &URLService{querier: querier, urlNormalizer: fakeNormalizer{}}
+
+	if _, err := s.BackfillURLHashes(context.Background()); err != nil {
+		t.Fatalf("BackfillURLHashes() error = %v", err)
+	}
+
+	// 与新建短链接时的哈希一致，才能找到重复的目标地址
+	if want := urlHash("https://example.com/a"); querier.hashes[1] != want {
+		t.Errorf("hash of normalized url = %s, want %s", querier.hashes[1], want)
+	}
+	if want := urlHash("https://example.com/a b"); querier.hashes[2] != want {
+		t.Errorf("hash of unnormalizable url = %s, want %s", querier.hashes[2], want)
+	}
+}
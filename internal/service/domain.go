@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/aeilang/urlshortener/internal/repo"
+	"github.com/aeilang/urlshortener/pkg/domainverify"
+)
+
+// DomainVerifier 通过DNS TXT记录验证用户对域名的所有权
+type DomainVerifier interface {
+	NewToken() (string, error)
+	RecordName(host string) string
+	RecordValue(token string) string
+	Verify(ctx context.Context, host, token string) error
+}
+
+// GetDomains 获取用户添加的所有自定义域名
+func (s *URLService) GetDomains(ctx context.Context, userID int) (*model.GetDomainsResponse, error) {
+	rows, err := s.querier.ListDomainsByUserID(ctx, int32(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]model.Domain, len(rows))
+	for i, row := range rows {
+		items[i] = s.toDomain(row)
+	}
+
+	return &model.GetDomainsResponse{Items: items}, nil
+}
+
+// CreateDomain 添加自定义域名，返回验证所需的TXT记录，重复添加时返回已有的域名
+func (s *URLService) CreateDomain(ctx context.Context, req model.CreateDomainRequest) (*model.Domain, error) {
+	host := normalizeHost(req.Host)
+	if host == s.baseHost {
+		return nil, model.ErrInvalidDomain
+	}
+
+	row, err := s.querier.GetDomainByHost(ctx, repo.GetDomainByHostParams{
+		Host:   host,
+		UserID: int32(req.UserID),
+	})
+	if err == nil {
+		domain := s.toDomain(row)
+		return &domain, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	taken, err := s.querier.IsDomainHostVerified(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, model.ErrDomainTaken
+	}
+
+	token, err := s.domainVerifier.NewToken()
+	if err != nil {
+		return nil, err
+	}
+
+	row, err = s.querier.CreateDomain(ctx, repo.CreateDomainParams{
+		UserID:      int32(req.UserID),
+		Host:        host,
+		VerifyToken: token,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	domain := s.toDomain(row)
+	return &domain, nil
+}
+
+// VerifyDomain 查询域名的TXT记录，验证通过后即可在该域名下创建短链接
+func (s *URLService) VerifyDomain(ctx context.Context, req model.VerifyDomainRequest) (*model.Domain, error) {
+	row, err := s.getDomain(ctx, req.ID, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if row.VerifiedAt.Valid {
+		domain := s.toDomain(row)
+		return &domain, nil
+	}
+
+	taken, err := s.querier.IsDomainHostVerified(ctx, row.Host)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, model.ErrDomainTaken
+	}
+
+	err = s.domainVerifier.Verify(ctx, row.Host, row.VerifyToken)
+	if errors.Is(err, domainverify.ErrRecordNotFound) {
+		return nil, model.ErrDomainVerifyFailed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	row, err = s.querier.VerifyDomain(ctx, row.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.DelHost(ctx, row.Host); err != nil {
+		return nil, err
+	}
+
+	domain := s.toDomain(row)
+	return &domain, nil
+}
+
+// DeleteDomain 删除自定义域名，域名下还有短链接(包括回收站中的)时不能删除
+func (s *URLService) DeleteDomain(ctx context.Context, req model.DeleteDomainRequest) error {
+	row, err := s.getDomain(ctx, req.ID, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	count, err := s.querier.CountURLsByDomainID(ctx, sql.NullInt64{Int64: row.ID, Valid: true})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return model.ErrDomainInUse
+	}
+
+	n, err := s.querier.DeleteDomain(ctx, repo.DeleteDomainParams{
+		ID:     row.ID,
+		UserID: int32(req.UserID),
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return model.ErrDomainNotFound
+	}
+
+	return s.cache.DelHost(ctx, row.Host)
+}
+
+func (s *URLService) getDomain(ctx context.Context, id int64, userID int) (repo.Domain, error) {
+	row, err := s.querier.GetDomainByID(ctx, repo.GetDomainByIDParams{
+		ID:     id,
+		UserID: int32(userID),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return repo.Domain{}, model.ErrDomainNotFound
+	}
+	return row, err
+}
+
+// verifiedDomain 查询属于该用户且已通过验证的域名，返回域名ID和规范化后的域名
+func (s *URLService) verifiedDomain(ctx context.Context, host string, userID int) (sql.NullInt64, string, error) {
+	row, err := s.querier.GetDomainByHost(ctx, repo.GetDomainByHostParams{
+		Host:   normalizeHost(host),
+		UserID: int32(userID),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return sql.NullInt64{}, "", model.ErrDomainNotFound
+	}
+	if err != nil {
+		return sql.NullInt64{}, "", err
+	}
+	if !row.VerifiedAt.Valid {
+		return sql.NullInt64{}, "", model.ErrDomainNotVerified
+	}
+
+	return sql.NullInt64{Int64: row.ID, Valid: true}, row.Host, nil
+}
+
+// resolveHost 将请求的Host头解析为已验证的自定义域名，默认域名和未知的域名都解析为空
+// 解析结果与短链接的缓存键一致，未知域名也能命中默认域名下短链接的缓存
+func (s *URLService) resolveHost(ctx context.Context, host string) (string, error) {
+	host = normalizeHost(host)
+	if host == "" || host == s.baseHost {
+		return "", nil
+	}
+
+	domain, ok, err := s.cache.GetHost(ctx, host)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return domain, nil
+	}
+
+	verified, err := s.querier.IsDomainHostVerified(ctx, host)
+	if err != nil {
+		return "", err
+	}
+	if verified {
+		domain = host
+	}
+
+	if err := s.cache.SetHost(ctx, host, domain); err != nil {
+		return "", err
+	}
+
+	return domain, nil
+}
+
+func (s *URLService) toDomain(row repo.Domain) model.Domain {
+	domain := model.Domain{
+		ID:          row.ID,
+		Host:        row.Host,
+		Verified:    row.VerifiedAt.Valid,
+		RecordName:  s.domainVerifier.RecordName(row.Host),
+		RecordValue: s.domainVerifier.RecordValue(row.VerifyToken),
+		CreatedAt:   row.CreatedAt,
+	}
+	if row.VerifiedAt.Valid {
+		domain.VerifiedAt = &row.VerifiedAt.Time
+	}
+
+	return domain
+}
+
+// shortURL 拼接短链接的完整地址，自定义域名沿用默认域名的协议
+func (s *URLService) shortURL(domain, shortCode string) string {
+	if domain == "" {
+		return s.baseURL + "/" + shortCode
+	}
+	return s.baseScheme + "://" + domain + "/" + shortCode
+}
+
+// normalizeHost 去掉端口和末尾的点并转为小写，请求的Host头和用户填写的域名都需要规范化后再比较
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// splitBaseURL 从默认域名的地址中取出协议和域名
+func splitBaseURL(baseURL string) (scheme, host string) {
+	scheme, rest, ok := strings.Cut(baseURL, "://")
+	if !ok {
+		return "https", normalizeHost(baseURL)
+	}
+
+	host, _, _ = strings.Cut(rest, "/")
+	return scheme, normalizeHost(host)
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aeilang/urlshortener/internal/model"
+)
+
+func TestResolveHost(t *testing.T) {
+	tests := []struct {
+		name string
+		host string
+		want string
+	}{
+		{name: "empty", host: "", want: ""},
+		{name: "base host", host: "s.example", want: ""},
+		{name: "base host with port", host: "S.Example:8080", want: ""},
+		{name: "verified domain", host: "go.example.com", want: "go.example.com"},
+		{name: "verified domain normalized", host: "GO.example.com.:443", want: "go.example.com"},
+		{name: "unverified domain", host: "pending.example.com", want: ""},
+		{name: "unknown host", host: "www.s.example", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			querier := &fakeQuerier{verifiedHosts: map[string]bool{"go.example.com": true}}
+			s := &URLService{querier: querier, cache: newFakeCache(), baseHost: "s.example"}
+
+			got, err := s.resolveHost(context.Background(), tt.host)
+			if err != nil {
+				t.Fatalf("resolveHost() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveHost(%q) = %q, want %q", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveHostCached(t *testing.T) {
+	querier := &fakeQuerier{verifiedHosts: map[string]bool{"go.example.com": true}}
+	cache := newFakeCache()
+	s := &URLService{querier: querier, cache: cache, baseHost: "s.example"}
+
+	for _, host := range []string{"go.example.com", "go.example.com", "evil.example.com", "evil.example.com"} {
+		if _, err := s.resolveHost(context.Background(), host); err != nil {
+			t.Fatalf("resolveHost() error = %v", err)
+		}
+	}
+
+	if querier.hostLookups != 2 {
+		t.Errorf("IsDomainHostVerified called %d times, want 2", querier.hostLookups)
+	}
+	want := map[string]string{"go.example.com": "go.example.com", "evil.example.com": ""}
+	if !reflect.DeepEqual(cache.hosts, want) {
+		t.Errorf("cached hosts = %v, want %v", cache.hosts, want)
+	}
+}
+
+func TestGetURLCacheKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		host    string
+		wantKey string
+	}{
+		{name: "base host", host: "s.example", wantKey: "abcd"},
+		{name: "unverified host", host: "attacker.example.com", wantKey: "abcd"},
+		{name: "verified domain", host: "go.example.com", wantKey: "go.example.com/abcd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newFakeCache()
+			cache.urls["abcd"] = model.URL{ShortCode: "abcd"}
+			cache.urls["go.example.com/abcd"] = model.URL{ShortCode: "abcd", Domain: "go.example.com"}
+			querier := &fakeQuerier{verifiedHosts: map[string]bool{"go.example.com": true}}
+			s := &URLService{querier: querier, cache: cache, baseHost: "s.example"}
+
+			if _, err := s.getURL(context.Background(), tt.host, "abcd"); err != nil {
+				t.Fatalf("getURL() error = %v", err)
+			}
+			if !reflect.DeepEqual(cache.getURLKeys, []string{tt.wantKey}) {
+				t.Errorf("cache keys = %v, want [%s]", cache.getURLKeys, tt.wantKey)
+			}
+		})
+	}
+}
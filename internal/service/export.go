@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/aeilang/urlshortener/internal/repo"
+)
+
+const exportBatchSize = 1000
+
+// ExportURLs 按ID分批读取用户的所有短链接并逐条交给fn，不会把全部结果放在内存中。
+// fn返回错误时停止导出
+func (s *URLService) ExportURLs(ctx context.Context, userID int, fn func(model.ExportedURL) error) error {
+	var lastID int64
+	for {
+		rows, err := s.querier.ExportURLsByUserID(ctx, repo.ExportURLsByUserIDParams{
+			UserID: int32(userID),
+			ID:     lastID,
+			Limit:  exportBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		keys := make([]string, len(rows))
+		for i, row := range rows {
+			keys[i] = model.URLKey(row.Host, row.ShortCode)
+		}
+
+		pending, err := s.cache.GetViewsMulti(ctx, keys)
+		if err != nil {
+			return err
+		}
+
+		for i, row := range rows {
+			if row.Tags == nil {
+				row.Tags = []string{}
+			}
+			if err := fn(model.ExportedURL{
+				ShortURL:    s.shortURL(row.Host, row.ShortCode),
+				OriginalURL: row.OriginalUrl,
+				ShortCode:   row.ShortCode,
+				Domain:      row.Host,
+				Title:       row.Title,
+				Notes:       row.Notes,
+				Tags:        row.Tags,
+				IsCustom:    row.IsCustom,
+				Views:       uint(row.Views) + uint(pending[i]),
+				CreatedAt:   row.CreatedAt,
+				ExpiredAt:   row.ExpiredAt,
+			}); err != nil {
+				return err
+			}
+		}
+
+		if len(rows) < exportBatchSize {
+			return nil
+		}
+		lastID = rows[len(rows)-1].ID
+	}
+}
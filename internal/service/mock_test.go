@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/aeilang/urlshortener/internal/repo"
+	"github.com/aeilang/urlshortener/pkg/useragent"
+)
+
+// fakeCache 内存中的URLCacher，键与RedisCache去掉前缀后的键一致
+type fakeCache struct {
+	urls     map[string]model.URL
+	views    map[string]int
+	visitors map[string]int64
+	attempts map[string]int64
+	hosts    map[string]string
+	counts   map[int]map[string]int64
+
+	getURLKeys    []string
+	getViewsCalls int
+	visitorCalls  int
+}
+
+var _ URLCacher = (*fakeCache)(nil)
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{
+		urls:     map[string]model.URL{},
+		views:    map[string]int{},
+		visitors: map[string]int64{},
+		attempts: map[string]int64{},
+		hosts:    map[string]string{},
+		counts:   map[int]map[string]int64{},
+	}
+}
+
+func (c *fakeCache) SetURL(ctx context.Context, url model.URL) error {
+	c.urls[url.Key()] = url
+	return nil
+}
+
+func (c *fakeCache) GetURL(ctx context.Context, shortCode string) (*model.URL, error) {
+	c.getURLKeys = append(c.getURLKeys, shortCode)
+	url, ok := c.urls[shortCode]
+	if !ok {
+		return nil, nil
+	}
+	return &url, nil
+}
+
+func (c *fakeCache) DelURL(ctx context.Context, shortCode string) error {
+	delete(c.urls, shortCode)
+	return nil
+}
+
+func (c *fakeCache) DelURLs(ctx context.Context, shortCodes []string) error {
+	for _, code := range shortCodes {
+		delete(c.urls, code)
+	}
+	return nil
+}
+
+func (c *fakeCache) IncreViews(ctx context.Context, shortCode string) error {
+	c.views[shortCode]++
+	return nil
+}
+
+func (c *fakeCache) ScanViews(ctx context.Context, cursor uint64, batchSize int64) ([]string, uint64, error) {
+	keys := make([]string, 0, len(c.views))
+	for key := range c.views {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, 0, nil
+}
+
+func (c *fakeCache) GetViews(ctx context.Context, shortCode string) (int, error) {
+	c.getViewsCalls++
+	return c.views[shortCode], nil
+}
+
+func (c *fakeCache) GetViewsMulti(ctx context.Context, shortCodes []string) ([]int, error) {
+	c.getViewsCalls++
+	views := make([]int, len(shortCodes))
+	for i, code := range shortCodes {
+		views[i] = c.views[code]
+	}
+	return views, nil
+}
+
+func (c *fakeCache) DelViews(ctx context.Context, shortCode string) error {
+	delete(c.views, shortCode)
+	return nil
+}
+
+func (c *fakeCache) AddVisitor(ctx context.Context, shortCode, fingerprint string, at time.Time) error {
+	c.visitors[shortCode]++
+	return nil
+}
+
+func (c *fakeCache) CountVisitors(ctx context.Context, shortCode string) (int64, error) {
+	c.visitorCalls++
+	return c.visitors[shortCode], nil
+}
+
+func (c *fakeCache) CountVisitorsMulti(ctx context.Context, shortCodes []string) ([]int64, error) {
+	c.visitorCalls++
+	visitors := make([]int64, len(shortCodes))
+	for i, code := range shortCodes {
+		visitors[i] = c.visitors[code]
+	}
+	return visitors, nil
+}
+
+func (c *fakeCache) CountVisitorsBetween(ctx context.Context, shortCode string, from, to time.Time) (int64, error) {
+	return c.visitors[shortCode], nil
+}
+
+func (c *fakeCache) DelVisitors(ctx context.Context, shortCode string) error {
+	delete(c.visitors, shortCode)
+	return nil
+}
+
+func (c *fakeCache) IncrePasswordAttempts(ctx context.Context, shortCode, ip string) (int64, error) {
+	c.attempts[shortCode+":"+ip]++
+	return c.attempts[shortCode+":"+ip], nil
+}
+
+func (c *fakeCache) DelPasswordAttempts(ctx context.Context, shortCode, ip string) error {
+	delete(c.attempts, shortCode+":"+ip)
+	return nil
+}
+
+func (c *fakeCache) SetQRCode(ctx context.Context, shortCode, style string, data []byte) error {
+	return nil
+}
+
+func (c *fakeCache) GetQRCode(ctx context.Context, shortCode, style string) ([]byte, error) {
+	return nil, nil
+}
+
+func (c *fakeCache) SetURLCount(ctx context.Context, userID int, filter string, count int64) error {
+	if c.counts[userID] == nil {
+		c.counts[userID] = map[string]int64{}
+	}
+	c.counts[userID][filter] = count
+	return nil
+}
+
+func (c *fakeCache) GetURLCount(ctx context.Context, userID int, filter string) (int64, bool, error) {
+	count, ok := c.counts[userID][filter]
+	return count, ok, nil
+}
+
+func (c *fakeCache) DelURLCount(ctx context.Context, userID int) error {
+	delete(c.counts, userID)
+	return nil
+}
+
+func (c *fakeCache) SetHost(ctx context.Context, host, domain string) error {
+	c.hosts[host] = domain
+	return nil
+}
+
+func (c *fakeCache) GetHost(ctx context.Context, host string) (string, bool, error) {
+	domain, ok := c.hosts[host]
+	return domain, ok, nil
+}
+
+func (c *fakeCache) DelHost(ctx context.Context, host string) error {
+	delete(c.hosts, host)
+	return nil
+}
+
+// fakeHasher 明文比较密码
+type fakeHasher struct{}
+
+func (fakeHasher) HashPassword(password string) (string, error) {
+	return "hashed:" + password, nil
+}
+
+func (fakeHasher) ComparePassword(hashedPassword, password string) bool {
+	return hashedPassword == "hashed:"+password
+}
+
+// fakeNormalizer 只把URL转为小写，包含空格的URL视为无法规范化
+type fakeNormalizer struct{}
+
+func (fakeNormalizer) Normalize(rawURL string) (string, error) {
+	if strings.Contains(rawURL, " ") {
+		return "", errors.New("invalid url")
+	}
+	return strings.ToLower(rawURL), nil
+}
+
+// fakeGeoLocator 按IP返回固定的国家
+type fakeGeoLocator map[string]string
+
+func (g fakeGeoLocator) Lookup(ip string) (country, city string) {
+	return g[ip], ""
+}
+
+// fakeUserAgentParser 按UA返回固定的解析结果
+type fakeUserAgentParser map[string]useragent.Info
+
+func (p fakeUserAgentParser) Parse(ua string) useragent.Info {
+	return p[ua]
+}
+
+// fakeQuerier 只实现测试用到的查询，调用其余方法会因为内嵌的nil接口而panic
+type fakeQuerier struct {
+	repo.Querier
+
+	trashed     []repo.PurgeTrashedURLsRow
+	purgeParams []repo.PurgeTrashedURLsParams
+
+	verifiedHosts map[string]bool
+	hostLookups   int
+	viewUpdates   []repo.UpdateViewsByHostAndShortCodeParams
+
+	listRows   []urlListRow
+	listQuery  string
+	listParams any
+	urlTags    []repo.ListTagsByURLIDsRow
+
+	unhashed    []repo.ListURLsWithoutHashRow
+	hashes      map[int64]string
+	hashBatches int
+}
+
+func (q *fakeQuerier) PurgeTrashedURLs(ctx context.Context, arg repo.PurgeTrashedURLsParams) ([]repo.PurgeTrashedURLsRow, error) {
+	q.purgeParams = append(q.purgeParams, arg)
+	n := min(int(arg.RowLimit), len(q.trashed))
+	rows := q.trashed[:n]
+	q.trashed = q.trashed[n:]
+	return rows, nil
+}
+
+func (q *fakeQuerier) IsDomainHostVerified(ctx context.Context, host string) (bool, error) {
+	q.hostLookups++
+	return q.verifiedHosts[host], nil
+}
+
+func (q *fakeQuerier) UpdateViewsByHostAndShortCode(ctx context.Context, arg repo.UpdateViewsByHostAndShortCodeParams) error {
+	q.viewUpdates = append(q.viewUpdates, arg)
+	return nil
+}
+
+func (q *fakeQuerier) ListTagsByURLIDs(ctx context.Context, urlIds []int64) ([]repo.ListTagsByURLIDsRow, error) {
+	return q.urlTags, nil
+}
+
+// list 记录调用的列表查询和参数，按limit返回预设的行
+func list[R urlListRowTypes](q *fakeQuerier, query string, params any, limit int32) ([]R, error) {
+	q.listQuery = query
+	q.listParams = params
+
+	rows := q.listRows[:min(int(limit), len(q.listRows))]
+	items := make([]R, len(rows))
+	for i, row := range rows {
+		items[i] = R(row)
+	}
+	return items, nil
+}
+
+func (q *fakeQuerier) GetURLsByUserIDCreatedAtAsc(ctx context.Context, arg repo.GetURLsByUserIDCreatedAtAscParams) ([]repo.GetURLsByUserIDCreatedAtAscRow, error) {
+	return list[repo.GetURLsByUserIDCreatedAtAscRow](q, "GetURLsByUserIDCreatedAtAsc", arg, arg.Limit)
+}
+
+func (q *fakeQuerier) GetURLsByUserIDCreatedAtDesc(ctx context.Context, arg repo.GetURLsByUserIDCreatedAtDescParams) ([]repo.GetURLsByUserIDCreatedAtDescRow, error) {
+	return list[repo.GetURLsByUserIDCreatedAtDescRow](q, "GetURLsByUserIDCreatedAtDesc", arg, arg.Limit)
+}
+
+func (q *fakeQuerier) GetURLsByUserIDExpiredAtAsc(ctx context.Context, arg repo.GetURLsByUserIDExpiredAtAscParams) ([]repo.GetURLsByUserIDExpiredAtAscRow, error) {
+	return list[repo.GetURLsByUserIDExpiredAtAscRow](q, "GetURLsByUserIDExpiredAtAsc", arg, arg.Limit)
+}
+
+func (q *fakeQuerier) GetURLsByUserIDExpiredAtDesc(ctx context.Context, arg repo.GetURLsByUserIDExpiredAtDescParams) ([]repo.GetURLsByUserIDExpiredAtDescRow, error) {
+	return list[repo.GetURLsByUserIDExpiredAtDescRow](q, "GetURLsByUserIDExpiredAtDesc", arg, arg.Limit)
+}
+
+func (q *fakeQuerier) GetURLsByUserIDOriginalURLAsc(ctx context.Context, arg repo.GetURLsByUserIDOriginalURLAscParams) ([]repo.GetURLsByUserIDOriginalURLAscRow, error) {
+	return list[repo.GetURLsByUserIDOriginalURLAscRow](q, "GetURLsByUserIDOriginalURLAsc", arg, arg.Limit)
+}
+
+func (q *fakeQuerier) GetURLsByUserIDOriginalURLDesc(ctx context.Context, arg repo.GetURLsByUserIDOriginalURLDescParams) ([]repo.GetURLsByUserIDOriginalURLDescRow, error) {
+	return list[repo.GetURLsByUserIDOriginalURLDescRow](q, "GetURLsByUserIDOriginalURLDesc", arg, arg.Limit)
+}
+
+func (q *fakeQuerier) GetURLsByUserIDViewsAsc(ctx context.Context, arg repo.GetURLsByUserIDViewsAscParams) ([]repo.GetURLsByUserIDViewsAscRow, error) {
+	return list[repo.GetURLsByUserIDViewsAscRow](q, "GetURLsByUserIDViewsAsc", arg, arg.Limit)
+}
+
+func (q *fakeQuerier) GetURLsByUserIDViewsDesc(ctx context.Context, arg repo.GetURLsByUserIDViewsDescParams) ([]repo.GetURLsByUserIDViewsDescRow, error) {
+	return list[repo.GetURLsByUserIDViewsDescRow](q, "GetURLsByUserIDViewsDesc", arg, arg.Limit)
+}
+
+// ListURLsWithoutHash 返回尚未回填的行，回填后由UpdateURLHash移除
+func (q *fakeQuerier) ListURLsWithoutHash(ctx context.Context, limit int32) ([]repo.ListURLsWithoutHashRow, error) {
+	q.hashBatches++
+	n := min(int(limit), len(q.unhashed))
+	return append([]repo.ListURLsWithoutHashRow(nil), q.unhashed[:n]...), nil
+}
+
+func (q *fakeQuerier) UpdateURLHash(ctx context.Context, arg repo.UpdateURLHashParams) error {
+	if q.hashes == nil {
+		q.hashes = map[int64]string{}
+	}
+	q.hashes[arg.ID] = arg.UrlHash
+	for i, row := range q.unhashed {
+		if row.ID == arg.ID {
+			q.unhashed = append(q.unhashed[:i], q.unhashed[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
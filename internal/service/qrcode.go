@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/aeilang/urlshortener/pkg/qrcode"
+)
+
+type QRCodeGenerator interface {
+	PNG(content string, opts qrcode.Options) ([]byte, error)
+	SVG(content string, opts qrcode.Options) ([]byte, error)
+}
+
+var qrcodeContentTypes = map[string]string{
+	"png": "image/png",
+	"svg": "image/svg+xml",
+}
+
+// GetQRCode 生成短链接的二维码，相同样式的二维码会被缓存
+func (s *URLService) GetQRCode(ctx context.Context, req model.GetQRCodeRequest) (*model.QRCode, error) {
+	ref, err := s.getURLRef(ctx, req.Code, req.Domain, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	fg, err := qrcode.ParseHexColor(req.Foreground)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", model.ErrInvalidQRCode, err)
+	}
+	bg, err := qrcode.ParseHexColor(req.Background)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", model.ErrInvalidQRCode, err)
+	}
+
+	opts := qrcode.Options{
+		Size:       req.Size,
+		Margin:     *req.Margin,
+		Level:      req.Level,
+		Foreground: fg,
+		Background: bg,
+		Logo:       req.Logo,
+	}
+
+	style := fmt.Sprintf("%s:%d:%d:%s:%s:%s:%t", req.Format, opts.Size, opts.Margin, opts.Level,
+		strings.ToLower(req.Foreground), strings.ToLower(req.Background), opts.Logo)
+	qr := &model.QRCode{ContentType: qrcodeContentTypes[req.Format]}
+
+	// 先访问cache
+	qr.Data, err = s.cache.GetQRCode(ctx, ref.key(), style)
+	if err != nil {
+		return nil, err
+	}
+	if qr.Data != nil {
+		return qr, nil
+	}
+
+	content := s.shortURL(ref.domain, ref.shortCode)
+	if req.Format == "svg" {
+		qr.Data, err = s.qrcodeGenerator.SVG(content, opts)
+	} else {
+		qr.Data, err = s.qrcodeGenerator.PNG(content, opts)
+	}
+	if errors.Is(err, qrcode.ErrLogoNotConfigured) || errors.Is(err, qrcode.ErrSizeTooSmall) {
+		return nil, fmt.Errorf("%w: %v", model.ErrInvalidQRCode, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 存入缓存
+	if err := s.cache.SetQRCode(ctx, ref.key(), style, qr.Data); err != nil {
+		return nil, err
+	}
+
+	return qr, nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/aeilang/urlshortener/internal/repo"
+)
+
+// urlContent 会记录历史版本的短链接内容，nil表示不修改
+type urlContent struct {
+	originalURL *string
+	rawURL      *string // 用户输入的原始URL，为空时与originalURL相同
+	title       *string
+	notes       *string
+}
+
+// updateContent 在事务中修改短链接的内容并记录新的历史版本，内容没有变化时不做任何操作
+func updateContent(ctx context.Context, q *repo.Queries, urlID int64, userID int, content urlContent, restoredFrom sql.NullInt64) error {
+	if content.originalURL == nil && content.title == nil && content.notes == nil {
+		return nil
+	}
+
+	// 锁住该行，避免并发修改时历史版本的顺序与实际不一致
+	current, err := q.GetURLContentForUpdate(ctx, urlID)
+	if err != nil {
+		return err
+	}
+
+	next := current
+	if content.originalURL != nil {
+		next.OriginalUrl = *content.originalURL
+	}
+	if content.title != nil {
+		next.Title = *content.title
+	}
+	if content.notes != nil {
+		next.Notes = *content.notes
+	}
+	if next == current {
+		return nil
+	}
+
+	// 只有目标地址变化时才修改用户输入的原始URL
+	var rawURL sql.NullString
+	if next.OriginalUrl != current.OriginalUrl {
+		rawURL = sql.NullString{String: next.OriginalUrl, Valid: true}
+		if content.rawURL != nil {
+			rawURL.String = *content.rawURL
+		}
+	}
+
+	if err := q.UpdateURLContentByID(ctx, repo.UpdateURLContentByIDParams{
+		OriginalUrl: next.OriginalUrl,
+		RawUrl:      rawURL,
+		Title:       next.Title,
+		Notes:       next.Notes,
+		UrlHash:     urlHash(next.OriginalUrl),
+		ID:          urlID,
+	}); err != nil {
+		return err
+	}
+
+	_, err = q.CreateURLRevision(ctx, repo.CreateURLRevisionParams{
+		UrlID:        urlID,
+		OriginalUrl:  next.OriginalUrl,
+		Title:        next.Title,
+		Notes:        next.Notes,
+		UserID:       int32(userID),
+		RestoredFrom: restoredFrom,
+	})
+	return err
+}
+
+// GetHistory 获取短链接的所有历史版本，最新的在前
+func (s *URLService) GetHistory(ctx context.Context, req model.GetHistoryRequest) (*model.GetHistoryResponse, error) {
+	ref, err := s.getURLRef(ctx, req.Code, req.Domain, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.querier.ListURLRevisions(ctx, ref.id)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]model.Revision, len(rows))
+	for i, row := range rows {
+		items[i] = model.Revision{
+			ID:          row.ID,
+			OriginalURL: row.OriginalUrl,
+			Title:       row.Title,
+			Notes:       row.Notes,
+			UserID:      int(row.UserID),
+			CreatedAt:   row.CreatedAt,
+		}
+		if row.RestoredFrom.Valid {
+			items[i].RestoredFrom = &row.RestoredFrom.Int64
+		}
+	}
+
+	return &model.GetHistoryResponse{Items: items}, nil
+}
+
+// RollbackURL 将短链接的内容恢复为指定的历史版本，回滚本身也会记录为一个新版本
+func (s *URLService) RollbackURL(ctx context.Context, req model.RollbackURLRequest) error {
+	ref, err := s.getURLRef(ctx, req.Code, req.Domain, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.execTx(ctx, func(q *repo.Queries) error {
+		rev, err := q.GetURLRevision(ctx, repo.GetURLRevisionParams{
+			ID:    req.ID,
+			UrlID: ref.id,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ErrRevisionNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		return updateContent(ctx, q, ref.id, req.UserID, urlContent{
+			originalURL: &rev.OriginalUrl,
+			title:       &rev.Title,
+			notes:       &rev.Notes,
+		}, sql.NullInt64{Int64: rev.ID, Valid: true})
+	}); err != nil {
+		return err
+	}
+
+	if err := s.cache.DelURLCount(ctx, req.UserID); err != nil {
+		return err
+	}
+
+	return s.cache.DelURL(ctx, ref.key())
+}
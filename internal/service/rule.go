@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/aeilang/urlshortener/internal/repo"
+	"github.com/aeilang/urlshortener/pkg/useragent"
+	"golang.org/x/text/language"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// GetRules 获取短链接的所有定向规则
+func (s *URLService) GetRules(ctx context.Context, req model.GetRulesRequest) (*model.GetRulesResponse, error) {
+	ref, err := s.getURLRef(ctx, req.Code, req.Domain, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.listRules(ctx, ref.id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.GetRulesResponse{Items: rules}, nil
+}
+
+// CreateRule 为短链接添加一条定向规则
+func (s *URLService) CreateRule(ctx context.Context, req model.CreateRuleRequest) (*model.Rule, error) {
+	rule := model.Rule{
+		Position:    req.Position,
+		Type:        req.Type,
+		Value:       req.Value,
+		Timezone:    req.Timezone,
+		Destination: req.Destination,
+	}
+	if err := normalizeRule(&rule); err != nil {
+		return nil, err
+	}
+
+	ref, err := s.getURLRef(ctx, req.Code, req.Domain, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := s.querier.CreateRule(ctx, repo.CreateRuleParams{
+		UrlID:          ref.id,
+		Position:       int32(rule.Position),
+		ConditionType:  rule.Type,
+		ConditionValue: rule.Value,
+		Timezone:       rule.Timezone,
+		Destination:    rule.Destination,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.DelURL(ctx, ref.key()); err != nil {
+		return nil, err
+	}
+
+	rule = toRule(row)
+	return &rule, nil
+}
+
+// UpdateRule 修改短链接的一条定向规则
+func (s *URLService) UpdateRule(ctx context.Context, req model.UpdateRuleRequest) (*model.Rule, error) {
+	rule := model.Rule{
+		Position:    req.Position,
+		Type:        req.Type,
+		Value:       req.Value,
+		Timezone:    req.Timezone,
+		Destination: req.Destination,
+	}
+	if err := normalizeRule(&rule); err != nil {
+		return nil, err
+	}
+
+	ref, err := s.getURLRef(ctx, req.Code, req.Domain, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := s.querier.UpdateRule(ctx, repo.UpdateRuleParams{
+		Position:       int32(rule.Position),
+		ConditionType:  rule.Type,
+		ConditionValue: rule.Value,
+		Timezone:       rule.Timezone,
+		Destination:    rule.Destination,
+		ID:             req.ID,
+		UrlID:          ref.id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrRuleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.DelURL(ctx, ref.key()); err != nil {
+		return nil, err
+	}
+
+	rule = toRule(row)
+	return &rule, nil
+}
+
+// DeleteRule 删除短链接的一条定向规则
+func (s *URLService) DeleteRule(ctx context.Context, req model.DeleteRuleRequest) error {
+	ref, err := s.getURLRef(ctx, req.Code, req.Domain, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	n, err := s.querier.DeleteRule(ctx, repo.DeleteRuleParams{
+		ID:    req.ID,
+		UrlID: ref.id,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return model.ErrRuleNotFound
+	}
+
+	return s.cache.DelURL(ctx, ref.key())
+}
+
+func (s *URLService) listRules(ctx context.Context, urlID int64) ([]model.Rule, error) {
+	rows, err := s.querier.ListRulesByURLID(ctx, urlID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]model.Rule, len(rows))
+	for i, row := range rows {
+		rules[i] = toRule(row)
+	}
+
+	return rules, nil
+}
+
+func toRule(row repo.UrlRule) model.Rule {
+	return model.Rule{
+		ID:          row.ID,
+		Position:    int(row.Position),
+		Type:        row.ConditionType,
+		Value:       row.ConditionValue,
+		Timezone:    row.Timezone,
+		Destination: row.Destination,
+	}
+}
+
+// normalizeRule 校验规则的条件并统一为小写、去掉空格的格式
+func normalizeRule(rule *model.Rule) error {
+	values := splitRuleValue(rule.Value)
+	if len(values) == 0 {
+		return model.ErrInvalidRule
+	}
+
+	switch rule.Type {
+	case model.RuleCountry:
+		for _, v := range values {
+			if len(v) != 2 {
+				return fmt.Errorf("%w: 国家代码应为两位字母 %q", model.ErrInvalidRule, v)
+			}
+		}
+	case model.RuleLanguage:
+		for _, v := range values {
+			if _, err := language.Parse(v); err != nil {
+				return fmt.Errorf("%w: 语言 %q", model.ErrInvalidRule, v)
+			}
+		}
+	case model.RuleWeekday:
+		for _, v := range values {
+			if _, ok := weekdays[v]; !ok {
+				return fmt.Errorf("%w: 星期应为 mon/tue/wed/thu/fri/sat/sun", model.ErrInvalidRule)
+			}
+		}
+	case model.RuleTime:
+		if len(values) != 1 {
+			return fmt.Errorf("%w: 时间段应为 HH:MM-HH:MM", model.ErrInvalidRule)
+		}
+		if _, _, err := parseTimeWindow(values[0]); err != nil {
+			return err
+		}
+	}
+
+	rule.Value = strings.Join(values, ",")
+
+	if rule.Type == model.RuleWeekday || rule.Type == model.RuleTime {
+		if rule.Timezone == "" {
+			rule.Timezone = "UTC"
+		}
+		if rule.Timezone == "Local" {
+			return model.ErrInvalidTimezone
+		}
+		if _, err := time.LoadLocation(rule.Timezone); err != nil {
+			return model.ErrInvalidTimezone
+		}
+	} else {
+		rule.Timezone = ""
+	}
+
+	return nil
+}
+
+func splitRuleValue(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		v = strings.ToLower(strings.TrimSpace(v))
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parseTimeWindow 解析 HH:MM-HH:MM 格式的时间段，返回从零点开始的分钟数
+func parseTimeWindow(value string) (start, end int, err error) {
+	from, to, ok := strings.Cut(value, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: 时间段应为 HH:MM-HH:MM", model.ErrInvalidRule)
+	}
+
+	fromTime, err := time.Parse("15:04", from)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: 时间段应为 HH:MM-HH:MM", model.ErrInvalidRule)
+	}
+	toTime, err := time.Parse("15:04", to)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: 时间段应为 HH:MM-HH:MM", model.ErrInvalidRule)
+	}
+
+	return fromTime.Hour()*60 + fromTime.Minute(), toTime.Hour()*60 + toTime.Minute(), nil
+}
+
+// ruleMatcher 按需解析访客信息，只有存在对应类型的规则时才查询GeoIP或解析User-Agent
+type ruleMatcher struct {
+	service *URLService
+	visitor model.Visitor
+
+	country  *string
+	ua       *useragent.Info
+	language *string
+}
+
+// matchRules 返回第一条命中的规则的跳转地址
+func (s *URLService) matchRules(url *model.URL, visitor model.Visitor) (string, bool) {
+	if len(url.Rules) == 0 {
+		return "", false
+	}
+
+	m := ruleMatcher{service: s, visitor: visitor}
+	for _, rule := range url.Rules {
+		if m.match(rule) {
+			return rule.Destination, true
+		}
+	}
+
+	return "", false
+}
+
+func (m *ruleMatcher) match(rule model.Rule) bool {
+	var actual []string
+
+	switch rule.Type {
+	case model.RuleCountry:
+		actual = []string{m.getCountry()}
+	case model.RuleOS:
+		actual = []string{strings.ToLower(m.getUserAgent().OS)}
+	case model.RuleDevice:
+		actual = []string{strings.ToLower(m.getUserAgent().Device)}
+	case model.RuleLanguage:
+		lang := m.getLanguage()
+		if lang == "" {
+			return false
+		}
+		// 规则 zh 同时匹配 zh-cn、zh-tw
+		for _, v := range strings.Split(rule.Value, ",") {
+			if lang == v || strings.HasPrefix(lang, v+"-") {
+				return true
+			}
+		}
+		return false
+	case model.RuleWeekday:
+		actual = []string{strings.ToLower(m.localTime(rule).Weekday().String()[:3])}
+	case model.RuleTime:
+		start, end, err := parseTimeWindow(rule.Value)
+		if err != nil {
+			return false
+		}
+		now := m.localTime(rule)
+		minute := now.Hour()*60 + now.Minute()
+		if start <= end {
+			return start <= minute && minute < end
+		}
+		return minute >= start || minute < end
+	default:
+		return false
+	}
+
+	for _, v := range strings.Split(rule.Value, ",") {
+		for _, a := range actual {
+			if a != "" && a == v {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (m *ruleMatcher) getCountry() string {
+	if m.country == nil {
+		country, _ := m.service.geoLocator.Lookup(m.visitor.IP)
+		country = strings.ToLower(country)
+		m.country = &country
+	}
+	return *m.country
+}
+
+func (m *ruleMatcher) getUserAgent() useragent.Info {
+	if m.ua == nil {
+		ua := m.service.userAgentParser.Parse(m.visitor.UserAgent)
+		m.ua = &ua
+	}
+	return *m.ua
+}
+
+// getLanguage 返回Accept-Language中权重最高的语言
+func (m *ruleMatcher) getLanguage() string {
+	if m.language == nil {
+		var lang string
+		tags, _, err := language.ParseAcceptLanguage(m.visitor.AcceptLanguage)
+		if err == nil && len(tags) > 0 {
+			lang = strings.ToLower(tags[0].String())
+		}
+		m.language = &lang
+	}
+	return *m.language
+}
+
+func (m *ruleMatcher) localTime(rule model.Rule) time.Time {
+	loc, err := time.LoadLocation(rule.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return m.visitor.Time.In(loc)
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/aeilang/urlshortener/pkg/useragent"
+)
+
+func TestMatchRules(t *testing.T) {
+	s := &URLService{
+		geoLocator: fakeGeoLocator{"203.0.113.1": "US", "203.0.113.2": "DE"},
+		userAgentParser: fakeUserAgentParser{
+			"iphone":  {Device: useragent.DeviceMobile, OS: "iOS"},
+			"windows": {Device: useragent.DeviceDesktop, OS: "Windows"},
+		},
+	}
+
+	// 2024-06-01 是星期六
+	saturdayNoon := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	rules := []model.Rule{
+		{Type: model.RuleCountry, Value: "us,ca", Destination: "https://us.example.com/"},
+		{Type: model.RuleOS, Value: "ios", Destination: "https://ios.example.com/"},
+		{Type: model.RuleLanguage, Value: "zh", Destination: "https://zh.example.com/"},
+		{Type: model.RuleWeekday, Value: "sun", Timezone: "Asia/Tokyo", Destination: "https://weekend.example.com/"},
+		{Type: model.RuleTime, Value: "22:00-06:00", Timezone: "UTC", Destination: "https://night.example.com/"},
+	}
+
+	tests := []struct {
+		name    string
+		visitor model.Visitor
+		want    string
+		wantOK  bool
+	}{
+		{
+			name:    "country",
+			visitor: model.Visitor{IP: "203.0.113.1", Time: saturdayNoon},
+			want:    "https://us.example.com/",
+			wantOK:  true,
+		},
+		{
+			name:    "first matching rule wins",
+			visitor: model.Visitor{IP: "203.0.113.1", UserAgent: "iphone", Time: saturdayNoon},
+			want:    "https://us.example.com/",
+			wantOK:  true,
+		},
+		{
+			name:    "os",
+			visitor: model.Visitor{IP: "203.0.113.2", UserAgent: "iphone", Time: saturdayNoon},
+			want:    "https://ios.example.com/",
+			wantOK:  true,
+		},
+		{
+			name:    "language prefix",
+			visitor: model.Visitor{IP: "203.0.113.2", AcceptLanguage: "zh-CN,en;q=0.8", Time: saturdayNoon},
+			want:    "https://zh.example.com/",
+			wantOK:  true,
+		},
+		{
+			name:    "weekday in rule timezone",
+			visitor: model.Visitor{IP: "203.0.113.2", Time: time.Date(2024, 6, 1, 16, 0, 0, 0, time.UTC)},
+			want:    "https://weekend.example.com/",
+			wantOK:  true,
+		},
+		{
+			name:    "time window across midnight",
+			visitor: model.Visitor{IP: "203.0.113.2", Time: time.Date(2024, 5, 31, 23, 30, 0, 0, time.UTC)},
+			want:    "https://night.example.com/",
+			wantOK:  true,
+		},
+		{
+			name:    "no match",
+			visitor: model.Visitor{IP: "203.0.113.2", UserAgent: "windows", AcceptLanguage: "en-US", Time: saturdayNoon},
+			wantOK:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := &model.URL{Rules: rules}
+			got, ok := s.matchRules(url, tt.visitor)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("matchRules() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNormalizeRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    model.Rule
+		want    model.Rule
+		wantErr error
+	}{
+		{
+			name: "country list",
+			rule: model.Rule{Type: model.RuleCountry, Value: " US, ca ,", Timezone: "Asia/Tokyo"},
+			want: model.Rule{Type: model.RuleCountry, Value: "us,ca"},
+		},
+		{
+			name: "weekday defaults to utc",
+			rule: model.Rule{Type: model.RuleWeekday, Value: "Sat,Sun"},
+			want: model.Rule{Type: model.RuleWeekday, Value: "sat,sun", Timezone: "UTC"},
+		},
+		{
+			name: "time window",
+			rule: model.Rule{Type: model.RuleTime, Value: "09:00-18:00", Timezone: "Europe/Berlin"},
+			want: model.Rule{Type: model.RuleTime, Value: "09:00-18:00", Timezone: "Europe/Berlin"},
+		},
+		{
+			name:    "empty value",
+			rule:    model.Rule{Type: model.RuleCountry, Value: " , "},
+			wantErr: model.ErrInvalidRule,
+		},
+		{
+			name:    "bad country code",
+			rule:    model.Rule{Type: model.RuleCountry, Value: "usa"},
+			wantErr: model.ErrInvalidRule,
+		},
+		{
+			name:    "bad weekday",
+			rule:    model.Rule{Type: model.RuleWeekday, Value: "someday"},
+			wantErr: model.ErrInvalidRule,
+		},
+		{
+			name:    "bad time window",
+			rule:    model.Rule{Type: model.RuleTime, Value: "9-18"},
+			wantErr: model.ErrInvalidRule,
+		},
+		{
+			name:    "server local timezone",
+			rule:    model.Rule{Type: model.RuleTime, Value: "09:00-18:00", Timezone: "Local"},
+			wantErr: model.ErrInvalidTimezone,
+		},
+		{
+			name:    "unknown timezone",
+			rule:    model.Rule{Type: model.RuleWeekday, Value: "mon", Timezone: "Mars/Base"},
+			wantErr: model.ErrInvalidTimezone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := tt.rule
+			err := normalizeRule(&rule)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("normalizeRule() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && rule != tt.want {
+				t.Errorf("normalizeRule() = %+v, want %+v", rule, tt.want)
+			}
+		})
+	}
+}
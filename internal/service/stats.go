@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/aeilang/urlshortener/internal/repo"
+)
+
+const (
+	maxStatsBuckets   = 1000
+	maxCityStats      = 100
+	defaultStatsRange = 7 * 24 * time.Hour
+)
+
+var bucketSizes = map[string]time.Duration{
+	"hour": time.Hour,
+	"day":  24 * time.Hour,
+	"week": 7 * 24 * time.Hour,
+}
+
+func (s *URLService) GetStats(ctx context.Context, req model.GetStatsRequest) (*model.GetStatsResponse, error) {
+	// time.Local 的名称为 "Local"，数据库无法识别
+	if req.Timezone == "Local" {
+		return nil, model.ErrInvalidTimezone
+	}
+	loc, err := time.LoadLocation(req.Timezone)
+	if err != nil {
+		return nil, model.ErrInvalidTimezone
+	}
+
+	from, to, err := statsRange(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	size, ok := bucketSizes[req.Interval]
+	if !ok || to.Sub(from)/size > maxStatsBuckets {
+		return nil, model.ErrInvalidStatsRange
+	}
+
+	ref, err := s.getURLRef(ctx, req.Code, req.Domain, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.querier.CountClicksByInterval(ctx, repo.CountClicksByIntervalParams{
+		Unit:     req.Interval,
+		Timezone: loc.String(),
+		UrlID:    ref.id,
+		StartAt:  from,
+		EndAt:    to,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 数据库返回的是所选时区下的本地时间，需要重新附加时区
+	counts := make(map[int64]repo.CountClicksByIntervalRow, len(rows))
+	for _, row := range rows {
+		b := row.Bucket
+		t := time.Date(b.Year(), b.Month(), b.Day(), b.Hour(), 0, 0, 0, loc)
+		counts[t.Unix()] = row
+	}
+
+	// 补齐没有点击的时间段
+	buckets := make([]model.StatsBucket, 0)
+	var total, botTotal int64
+	for t := truncateBucket(from.In(loc), req.Interval); t.Before(to); t = nextBucket(t, req.Interval) {
+		row := counts[t.Unix()]
+		buckets = append(buckets, model.StatsBucket{
+			Time:      t,
+			Clicks:    row.Clicks,
+			BotClicks: row.BotClicks,
+		})
+		total += row.Clicks
+		botTotal += row.BotClicks
+	}
+
+	visitors, err := s.cache.CountVisitorsBetween(ctx, ref.key(), from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	variants, err := s.variantStats(ctx, ref.id, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.GetStatsResponse{
+		Code:           req.Code,
+		Interval:       req.Interval,
+		Timezone:       loc.String(),
+		From:           from.In(loc),
+		To:             to.In(loc),
+		Total:          total,
+		BotClicks:      botTotal,
+		UniqueVisitors: visitors,
+		Buckets:        buckets,
+		Variants:       variants,
+	}, nil
+}
+
+func (s *URLService) GetGeoStats(ctx context.Context, req model.GetBreakdownRequest) (*model.GetGeoStatsResponse, error) {
+	from, to, err := statsRange(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	ref, err := s.getURLRef(ctx, req.Code, req.Domain, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	countryRows, err := s.querier.CountClicksByCountry(ctx, repo.CountClicksByCountryParams{
+		UrlID:   ref.id,
+		StartAt: from,
+		EndAt:   to,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cityRows, err := s.querier.CountClicksByCity(ctx, repo.CountClicksByCityParams{
+		UrlID:    ref.id,
+		StartAt:  from,
+		EndAt:    to,
+		RowLimit: maxCityStats,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	countries := make([]model.CountryStats, len(countryRows))
+	for i, row := range countryRows {
+		countries[i] = model.CountryStats{
+			Country: row.Country,
+			Clicks:  row.Clicks,
+		}
+	}
+
+	cities := make([]model.CityStats, len(cityRows))
+	for i, row := range cityRows {
+		cities[i] = model.CityStats{
+			Country: row.Country,
+			City:    row.City,
+			Clicks:  row.Clicks,
+		}
+	}
+
+	return &model.GetGeoStatsResponse{
+		Code:      req.Code,
+		From:      from,
+		To:        to,
+		Countries: countries,
+		Cities:    cities,
+	}, nil
+}
+
+func (s *URLService) GetDeviceStats(ctx context.Context, req model.GetBreakdownRequest) (*model.GetDeviceStatsResponse, error) {
+	from, to, err := statsRange(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	ref, err := s.getURLRef(ctx, req.Code, req.Domain, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.querier.CountClicksByUserAgent(ctx, repo.CountClicksByUserAgentParams{
+		UrlID:   ref.id,
+		StartAt: from,
+		EndAt:   to,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := deviceStats(rows)
+	resp.Code = req.Code
+	resp.From = from
+	resp.To = to
+
+	return resp, nil
+}
+
+func (s *URLService) GetUserDeviceStats(ctx context.Context, req model.GetUserBreakdownRequest) (*model.GetDeviceStatsResponse, error) {
+	from, to, err := statsRange(req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	userRows, err := s.querier.CountUserClicksByUserAgent(ctx, repo.CountUserClicksByUserAgentParams{
+		UserID:  int32(req.UserID),
+		StartAt: from,
+		EndAt:   to,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]repo.CountClicksByUserAgentRow, len(userRows))
+	for i, row := range userRows {
+		rows[i] = repo.CountClicksByUserAgentRow(row)
+	}
+
+	resp := deviceStats(rows)
+	resp.From = from
+	resp.To = to
+
+	return resp, nil
+}
+
+// deviceStats 将按(设备, 系统, 浏览器, 是否为机器人)分组的结果汇总为各个维度的分布
+func deviceStats(rows []repo.CountClicksByUserAgentRow) *model.GetDeviceStatsResponse {
+	devices := make(map[string]int64)
+	oses := make(map[string]int64)
+	browsers := make(map[string]int64)
+	resp := &model.GetDeviceStatsResponse{}
+
+	for _, row := range rows {
+		devices[row.Device] += row.Clicks
+		oses[row.Os] += row.Clicks
+		browsers[row.Browser] += row.Clicks
+		if row.IsBot {
+			resp.Bots += row.Clicks
+		} else {
+			resp.Humans += row.Clicks
+		}
+	}
+
+	resp.Devices = breakdownItems(devices)
+	resp.OS = breakdownItems(oses)
+	resp.Browsers = breakdownItems(browsers)
+
+	return resp
+}
+
+// breakdownItems 按点击次数从高到低排序
+func breakdownItems(counts map[string]int64) []model.BreakdownItem {
+	items := make([]model.BreakdownItem, 0, len(counts))
+	for name, clicks := range counts {
+		items = append(items, model.BreakdownItem{
+			Name:   name,
+			Clicks: clicks,
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Clicks != items[j].Clicks {
+			return items[i].Clicks > items[j].Clicks
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	return items
+}
+
+// statsRange 补全默认的统计时间范围，默认为最近7天
+func statsRange(from, to time.Time) (time.Time, time.Time, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultStatsRange)
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, model.ErrInvalidStatsRange
+	}
+
+	return from, to, nil
+}
+
+// truncateBucket 与 PostgreSQL 的 date_trunc 保持一致，周从周一开始
+func truncateBucket(t time.Time, interval string) time.Time {
+	switch interval {
+	case "hour":
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	case "week":
+		day := t.Day() - (int(t.Weekday())+6)%7
+		return time.Date(t.Year(), t.Month(), day, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	}
+}
+
+func nextBucket(t time.Time, interval string) time.Time {
+	switch interval {
+	case "hour":
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+	case "week":
+		return time.Date(t.Year(), t.Month(), t.Day()+7, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/aeilang/urlshortener/internal/repo"
+)
+
+// GetTags 获取用户的所有标签，按名称排序
+func (s *URLService) GetTags(ctx context.Context, userID int) (*model.GetTagsResponse, error) {
+	rows, err := s.querier.ListTagsByUserID(ctx, int32(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]model.Tag, len(rows))
+	for i, row := range rows {
+		items[i] = model.Tag{ID: row.ID, Name: row.Name}
+	}
+
+	return &model.GetTagsResponse{Items: items}, nil
+}
+
+// CreateTag 创建标签，同一用户下名称不能重复
+func (s *URLService) CreateTag(ctx context.Context, req model.CreateTagRequest) (*model.Tag, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := s.checkTagName(ctx, name, 0, req.UserID); err != nil {
+		return nil, err
+	}
+
+	row, err := s.querier.CreateTag(ctx, repo.CreateTagParams{
+		UserID: int32(req.UserID),
+		Name:   name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Tag{ID: row.ID, Name: row.Name}, nil
+}
+
+// RenameTag 修改标签的名称，只修改大小写时也允许
+func (s *URLService) RenameTag(ctx context.Context, req model.RenameTagRequest) (*model.Tag, error) {
+	name := strings.TrimSpace(req.Name)
+	if err := s.checkTagName(ctx, name, req.ID, req.UserID); err != nil {
+		return nil, err
+	}
+
+	row, err := s.querier.RenameTag(ctx, repo.RenameTagParams{
+		Name:   name,
+		ID:     req.ID,
+		UserID: int32(req.UserID),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrTagNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.DelURLCount(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+
+	return &model.Tag{ID: row.ID, Name: row.Name}, nil
+}
+
+// DeleteTag 删除标签，同时从所有短链接上移除
+func (s *URLService) DeleteTag(ctx context.Context, req model.DeleteTagRequest) error {
+	n, err := s.querier.DeleteTag(ctx, repo.DeleteTagParams{
+		ID:     req.ID,
+		UserID: int32(req.UserID),
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return model.ErrTagNotFound
+	}
+
+	return s.cache.DelURLCount(ctx, req.UserID)
+}
+
+// AttachTag 为短链接添加标签，重复添加不会报错
+func (s *URLService) AttachTag(ctx context.Context, req model.URLTagRequest) error {
+	ref, err := s.getURLRef(ctx, req.Code, req.Domain, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	owned, err := s.querier.IsTagOwnedByUser(ctx, repo.IsTagOwnedByUserParams{
+		ID:     req.ID,
+		UserID: int32(req.UserID),
+	})
+	if err != nil {
+		return err
+	}
+	if !owned {
+		return model.ErrTagNotFound
+	}
+
+	if err := s.querier.AttachTag(ctx, repo.AttachTagParams{
+		UrlID: ref.id,
+		TagID: req.ID,
+	}); err != nil {
+		return err
+	}
+
+	return s.cache.DelURLCount(ctx, req.UserID)
+}
+
+// DetachTag 移除短链接上的标签
+func (s *URLService) DetachTag(ctx context.Context, req model.URLTagRequest) error {
+	ref, err := s.getURLRef(ctx, req.Code, req.Domain, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	n, err := s.querier.DetachTag(ctx, repo.DetachTagParams{
+		UrlID: ref.id,
+		TagID: req.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return model.ErrTagNotFound
+	}
+
+	return s.cache.DelURLCount(ctx, req.UserID)
+}
+
+// checkTagName 检查名称是否已被该用户的其他标签使用，id为0表示新建标签
+func (s *URLService) checkTagName(ctx context.Context, name string, id int64, userID int) error {
+	if name == "" {
+		return model.ErrInvalidTag
+	}
+
+	existing, err := s.querier.GetTagIDByName(ctx, repo.GetTagIDByNameParams{
+		Name:   name,
+		UserID: int32(userID),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing != id {
+		return model.ErrTagExists
+	}
+
+	return nil
+}
+
+// listURLTags 批量查询短链接的标签，按短链接ID分组
+func (s *URLService) listURLTags(ctx context.Context, urlIDs []int64) (map[int64][]model.Tag, error) {
+	tags := make(map[int64][]model.Tag, len(urlIDs))
+	if len(urlIDs) == 0 {
+		return tags, nil
+	}
+
+	rows, err := s.querier.ListTagsByURLIDs(ctx, urlIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		tags[row.UrlID] = append(tags[row.UrlID], model.Tag{ID: row.ID, Name: row.Name})
+	}
+
+	return tags, nil
+}
+
+// uniqueTags 去掉空白和重复的标签名称，比较时忽略大小写
+func uniqueTags(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		tags = append(tags, name)
+	}
+	return tags
+}
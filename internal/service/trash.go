@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/aeilang/urlshortener/internal/repo"
+)
+
+// purgeBatchSize 每次从数据库中永久删除的短链接数量，避免长时间锁表
+const purgeBatchSize = 500
+
+// defaultTrashRetention 未配置保留期限时使用，避免定时清理时删除回收站中所有的短链接
+const defaultTrashRetention = 30 * 24 * time.Hour
+
+// GetTrash 获取用户回收站中的短链接，最近删除的在前
+func (s *URLService) GetTrash(ctx context.Context, req model.GetTrashRequest) (*model.GetTrashResponse, error) {
+	rows, err := s.querier.GetTrashedURLsByUserID(ctx, repo.GetTrashedURLsByUserIDParams{
+		UserID: int32(req.UserID),
+		Limit:  int32(req.Size),
+		Offset: int32((req.Page - 1) * req.Size),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]model.TrashedURL, len(rows))
+	total := 0
+	for i, row := range rows {
+		items[i] = model.TrashedURL{
+			ID:          row.ID,
+			OriginalURL: row.OriginalUrl,
+			ShortURL:    s.shortURL(row.Host, row.ShortCode),
+			Title:       row.Title,
+			DeletedAt:   row.DeletedAt.Time,
+			PurgeAt:     row.DeletedAt.Time.Add(s.trashRetention),
+		}
+		total = int(row.Total)
+	}
+
+	return &model.GetTrashResponse{
+		Items: items,
+		Total: total,
+	}, nil
+}
+
+// RestoreURL 将短链接从回收站中恢复，恢复后立即可以访问
+func (s *URLService) RestoreURL(ctx context.Context, req model.RestoreURLRequest) error {
+	ref, err := s.getTrashedURLRef(ctx, req.Code, req.Domain, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.querier.RestoreURLByID(ctx, ref.id); err != nil {
+		return err
+	}
+
+	if err := s.cache.DelURLCount(ctx, req.UserID); err != nil {
+		return err
+	}
+
+	return s.cache.DelURL(ctx, ref.key())
+}
+
+// PurgeURL 永久删除回收站中的短链接，删除后短链接代码可以被重新使用
+func (s *URLService) PurgeURL(ctx context.Context, req model.PurgeURLRequest) error {
+	ref, err := s.getTrashedURLRef(ctx, req.Code, req.Domain, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.querier.DeleteURLByID(ctx, ref.id); err != nil {
+		return err
+	}
+
+	return s.purgeCache(ctx, ref.key())
+}
+
+// PurgeTrash 永久删除回收站中超过保留期限的短链接，返回删除的数量
+func (s *URLService) PurgeTrash(ctx context.Context) (int, error) {
+	before := time.Now().Add(-s.trashRetention)
+	purged := 0
+
+	for {
+		rows, err := s.querier.PurgeTrashedURLs(ctx, repo.PurgeTrashedURLsParams{
+			DeletedBefore: sql.NullTime{Time: before, Valid: true},
+			RowLimit:      purgeBatchSize,
+		})
+		if err != nil {
+			return purged, err
+		}
+
+		for _, row := range rows {
+			if err := s.purgeCache(ctx, model.URLKey(row.Host, row.ShortCode)); err != nil {
+				return purged, err
+			}
+		}
+
+		purged += len(rows)
+		if len(rows) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// getTrashedURLRef 按域名和代码查询回收站中属于该用户的短链接
+func (s *URLService) getTrashedURLRef(ctx context.Context, shortCode, domain string, userID int) (urlRef, error) {
+	row, err := s.querier.GetTrashedURLByShortCodeAndUserID(ctx, repo.GetTrashedURLByShortCodeAndUserIDParams{
+		ShortCode: shortCode,
+		UserID:    int32(userID),
+		Host:      domain,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return urlRef{}, model.ErrURLNotFound
+	}
+	if err != nil {
+		return urlRef{}, err
+	}
+
+	return urlRef{id: row.ID, domain: row.Host, shortCode: shortCode}, nil
+}
+
+// purgeCache 删除短链接在redis中的所有数据
+func (s *URLService) purgeCache(ctx context.Context, key string) error {
+	if err := s.cache.DelURL(ctx, key); err != nil {
+		return err
+	}
+
+	if err := s.cache.DelViews(ctx, key); err != nil {
+		return err
+	}
+
+	return s.cache.DelVisitors(ctx, key)
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aeilang/urlshortener/config"
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/aeilang/urlshortener/internal/repo"
+)
+
+func TestPurgeTrash(t *testing.T) {
+	tests := []struct {
+		name      string
+		trashed   int
+		wantCalls int
+	}{
+		{name: "empty", trashed: 0, wantCalls: 1},
+		{name: "single batch", trashed: 3, wantCalls: 1},
+		{name: "full batch", trashed: purgeBatchSize, wantCalls: 2},
+		{name: "several batches", trashed: purgeBatchSize*2 + 1, wantCalls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newFakeCache()
+			querier := &fakeQuerier{}
+			for i := 0; i < tt.trashed; i++ {
+				row := repo.PurgeTrashedURLsRow{ShortCode: fmt.Sprintf("c%d", i)}
+				if i%2 == 1 {
+					row.Host = "go.example.com"
+				}
+				querier.trashed = append(querier.trashed, row)
+
+				key := model.URLKey(row.Host, row.ShortCode)
+				cache.urls[key] = model.URL{ShortCode: row.ShortCode, Domain: row.Host}
+				cache.views[key] = 1
+				cache.visitors[key] = 1
+			}
+			cache.urls["keep"] = model.URL{ShortCode: "keep"}
+			cache.views["keep"] = 1
+
+			s := &URLService{querier: querier, cache: cache, trashRetention: 24 * time.Hour}
+
+			purged, err := s.PurgeTrash(context.Background())
+			if err != nil {
+				t.Fatalf("PurgeTrash() error = %v", err)
+			}
+			if purged != tt.trashed {
+				t.Errorf("PurgeTrash() = %d, want %d", purged, tt.trashed)
+			}
+			if len(querier.purgeParams) != tt.wantCalls {
+				t.Errorf("PurgeTrashedURLs called %d times, want %d", len(querier.purgeParams), tt.wantCalls)
+			}
+			if len(cache.urls) != 1 || len(cache.views) != 1 || len(cache.visitors) != 0 {
+				t.Errorf("cache left urls=%d views=%d visitors=%d, want 1, 1, 0", len(cache.urls), len(cache.views), len(cache.visitors))
+			}
+		})
+	}
+}
+
+func TestPurgeTrashRetention(t *testing.T) {
+	querier := &fakeQuerier{}
+	s := &URLService{querier: querier, cache: newFakeCache(), trashRetention: 24 * time.Hour}
+
+	if _, err := s.PurgeTrash(context.Background()); err != nil {
+		t.Fatalf("PurgeTrash() error = %v", err)
+	}
+
+	before := querier.purgeParams[0].DeletedBefore.Time
+	if d := time.Since(before); d < 24*time.Hour || d > 24*time.Hour+time.Minute {
+		t.Errorf("DeletedBefore = %v ago, want 24h ago", d)
+	}
+}
+
+func TestNewURLServiceTrashRetentionDefault(t *testing.T) {
+	s := NewURLService(nil, nil, nil, nil, nil, nil, nil, nil, nil, config.AppConfig{BaseURL: "https://s.example"})
+
+	if s.trashRetention != defaultTrashRetention {
+		t.Errorf("trashRetention = %v, want %v", s.trashRetention, defaultTrashRetention)
+	}
+}
@@ -2,140 +2,460 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
 	"github.com/aeilang/urlshortener/config"
 	"github.com/aeilang/urlshortener/internal/model"
 	"github.com/aeilang/urlshortener/internal/repo"
+	"github.com/aeilang/urlshortener/pkg/useragent"
 )
 
+// 未配置密码尝试次数时使用的默认值，为0会导致所有尝试都被拒绝
+const defaultPasswordMaxAttempts = 5
+
 type ShortCodeGenerator interface {
 	GenerateShortCode() string
 }
 
 type URLCacher interface {
 	SetURL(ctx context.Context, url model.URL) error
-	GetURL(ctx context.Context, shortCode string) (string, error)
+	GetURL(ctx context.Context, shortCode string) (*model.URL, error)
 	DelURL(ctx context.Context, shortCode string) error
+	DelURLs(ctx context.Context, shortCodes []string) error
 	IncreViews(ctx context.Context, shortCode string) error
 	ScanViews(ctx context.Context, cursor uint64, batchSize int64) (keys []string, nextCursor uint64, err error)
 	GetViews(ctx context.Context, shortCode string) (int, error)
+	GetViewsMulti(ctx context.Context, shortCodes []string) ([]int, error)
 	DelViews(ctx context.Context, shortCode string) error
+	AddVisitor(ctx context.Context, shortCode, fingerprint string, at time.Time) error
+	CountVisitors(ctx context.Context, shortCode string) (int64, error)
+	CountVisitorsMulti(ctx context.Context, shortCodes []string) ([]int64, error)
+	CountVisitorsBetween(ctx context.Context, shortCode string, from, to time.Time) (int64, error)
+	DelVisitors(ctx context.Context, shortCode string) error
+	IncrePasswordAttempts(ctx context.Context, shortCode, ip string) (int64, error)
+	DelPasswordAttempts(ctx context.Context, shortCode, ip string) error
+	SetQRCode(ctx context.Context, shortCode, style string, data []byte) error
+	GetQRCode(ctx context.Context, shortCode, style string) ([]byte, error)
+	SetURLCount(ctx context.Context, userID int, filter string, count int64) error
+	GetURLCount(ctx context.Context, userID int, filter string) (count int64, ok bool, err error)
+	DelURLCount(ctx context.Context, userID int) error
+	SetHost(ctx context.Context, host, domain string) error
+	GetHost(ctx context.Context, host string) (domain string, ok bool, err error)
+	DelHost(ctx context.Context, host string) error
+}
+
+type GeoLocator interface {
+	Lookup(ip string) (country, city string)
+}
+
+type UserAgentParser interface {
+	Parse(ua string) useragent.Info
+}
+
+type URLNormalizer interface {
+	Normalize(rawURL string) (string, error)
 }
 
 type URLService struct {
+	db                 *sql.DB
 	querier            repo.Querier
 	shortCodeGenerator ShortCodeGenerator
 	cache              URLCacher
+	geoLocator         GeoLocator
+	userAgentParser    UserAgentParser
+	passwordHasher     PasswordHasher
+	qrcodeGenerator    QRCodeGenerator
+	domainVerifier     DomainVerifier
+	urlNormalizer      URLNormalizer
 	urlDefaultDuration time.Duration
 	baseURL            string
+	baseScheme         string
+	baseHost           string
+	maxPasswordTries   int64
+	trashRetention     time.Duration
 }
 
-func NewURLService(db *sql.DB, shortCodeGenerator ShortCodeGenerator, cache URLCacher, cfg config.AppConfig) *URLService {
+func NewURLService(db *sql.DB, shortCodeGenerator ShortCodeGenerator, cache URLCacher, geoLocator GeoLocator, userAgentParser UserAgentParser, passwordHasher PasswordHasher, qrcodeGenerator QRCodeGenerator, domainVerifier DomainVerifier, urlNormalizer URLNormalizer, cfg config.AppConfig) *URLService {
+	baseScheme, baseHost := splitBaseURL(cfg.BaseURL)
+
+	maxPasswordTries := cfg.PasswordMaxAttempts
+	if maxPasswordTries <= 0 {
+		maxPasswordTries = defaultPasswordMaxAttempts
+	}
+
+	trashRetention := cfg.TrashRetention
+	if trashRetention <= 0 {
+		trashRetention = defaultTrashRetention
+	}
+
 	return &URLService{
+		db:                 db,
 		querier:            repo.New(db),
 		shortCodeGenerator: shortCodeGenerator,
 		cache:              cache,
+		geoLocator:         geoLocator,
+		userAgentParser:    userAgentParser,
+		passwordHasher:     passwordHasher,
+		qrcodeGenerator:    qrcodeGenerator,
+		domainVerifier:     domainVerifier,
+		urlNormalizer:      urlNormalizer,
 		urlDefaultDuration: cfg.DefaultDuration,
 		baseURL:            cfg.BaseURL,
+		baseScheme:         baseScheme,
+		baseHost:           baseHost,
+		maxPasswordTries:   int64(maxPasswordTries),
+		trashRetention:     trashRetention,
 	}
 }
 
-func (s *URLService) CreateURL(ctx context.Context, req model.CreateURLRequest) (shortURL string, err error) {
+func (s *URLService) CreateURL(ctx context.Context, req model.CreateURLRequest) (*model.CreateURLResponse, error) {
 	var shortCode string
 	var isCustom bool
 	var expiredAt time.Time
 
+	var domainID sql.NullInt64
+	var domain string
+	if req.Domain != "" {
+		var err error
+		domainID, domain, err = s.verifiedDomain(ctx, req.Domain, req.UserID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 保存规范化后的URL，同时保留用户的输入
+	originalURL, err := s.urlNormalizer.Normalize(req.OriginalURL)
+	if err != nil {
+		return nil, model.ErrInvalidURL
+	}
+	warning := urlWarning(originalURL)
+
+	// 同一目标地址已有可用的短链接时直接返回，不再生成新的代码
+	hash := urlHash(originalURL)
+	if canDedup(req) {
+		shortURL, err := s.findDuplicate(ctx, req, domainID, hash)
+		if err != nil {
+			return nil, err
+		}
+		if shortURL != "" {
+			return &model.CreateURLResponse{ShortURL: shortURL, Existing: true, Warning: warning}, nil
+		}
+	}
+
 	if req.CustomCode != "" {
-		isAvailabel, err := s.querier.IsShortCodeAvailable(ctx, req.CustomCode)
+		isAvailabel, err := s.querier.IsShortCodeAvailable(ctx, repo.IsShortCodeAvailableParams{
+			ShortCode: req.CustomCode,
+			DomainID:  domainID,
+		})
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 		if !isAvailabel {
-			return "", fmt.Errorf("别名已存在")
+			return nil, fmt.Errorf("别名已存在")
 		}
 		shortCode = req.CustomCode
 		isCustom = true
 	} else {
-		code, err := s.getShortCode(ctx, 0)
+		code, err := s.getShortCode(ctx, domainID, 0)
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 		shortCode = code
 	}
 
 	if req.Duration == nil {
-		expiredAt = time.Now().Add(s.urlDefaultDuration)
+		expiredAt = time.Now().UTC().Add(s.urlDefaultDuration)
 	} else {
-		expiredAt = time.Now().Add(time.Hour * time.Duration(*req.Duration))
+		expiredAt = time.Now().UTC().Add(time.Hour * time.Duration(*req.Duration))
+	}
+
+	// 时间列不带时区，expired_at和active_from都转为UTC保存，缓存中的时间与数据库中的一致
+	var activeFrom sql.NullTime
+	if req.ActiveFrom != nil {
+		activeFrom = sql.NullTime{Time: req.ActiveFrom.UTC(), Valid: true}
+	}
+
+	var maxClicks sql.NullInt32
+	if req.MaxClicks != nil {
+		maxClicks = sql.NullInt32{Int32: int32(*req.MaxClicks), Valid: true}
 	}
 
-	// 插入数据库
-	if err := s.querier.CreateURL(ctx, repo.CreateURLParams{
-		OriginalUrl: req.OriginalURL,
-		ShortCode:   shortCode,
-		IsCustom:    isCustom,
-		ExpiredAt:   expiredAt,
-		UserID:      int32(req.UserID),
+	var passwordHash string
+	if req.Password != "" {
+		hash, err := s.passwordHasher.HashPassword(req.Password)
+		if err != nil {
+			return nil, err
+		}
+		passwordHash = hash
+	}
+
+	// 插入数据库，同时记录第一个历史版本
+	var id int64
+	if err := s.execTx(ctx, func(q *repo.Queries) error {
+		var err error
+		id, err = q.CreateURL(ctx, repo.CreateURLParams{
+			OriginalUrl:   originalURL,
+			ShortCode:     shortCode,
+			IsCustom:      isCustom,
+			ExpiredAt:     expiredAt,
+			UserID:        int32(req.UserID),
+			PasswordHash:  passwordHash,
+			MaxClicks:     maxClicks,
+			ActiveFrom:    activeFrom,
+			RedirectType:  req.RedirectType,
+			AlwaysPreview: req.AlwaysPreview,
+			Title:         req.Title,
+			Notes:         req.Notes,
+			DomainID:      domainID,
+			UrlHash:       hash,
+			RawUrl:        req.OriginalURL,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateURLRevision(ctx, repo.CreateURLRevisionParams{
+			UrlID:       id,
+			OriginalUrl: originalURL,
+			Title:       req.Title,
+			Notes:       req.Notes,
+			UserID:      int32(req.UserID),
+		})
+		return err
 	}); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	url := model.URL{
-		OriginalURL: req.OriginalURL,
-		ShortCode:   shortCode,
+		ID:            id,
+		OriginalURL:   originalURL,
+		ShortCode:     shortCode,
+		Domain:        domain,
+		PasswordHash:  passwordHash,
+		MaxClicks:     int(maxClicks.Int32),
+		RedirectType:  req.RedirectType,
+		AlwaysPreview: req.AlwaysPreview,
+		CreatedAt:     time.Now(),
+		ExpiredAt:     expiredAt,
+	}
+	if activeFrom.Valid {
+		url.ActiveFrom = &activeFrom.Time
 	}
 
 	// 存入缓存
 	if err := s.cache.SetURL(ctx, url); err != nil {
-		return "", err
+		return nil, err
 	}
 
-	ShortURL := s.baseURL + "/" + url.ShortCode
+	if err := s.cache.DelURLCount(ctx, req.UserID); err != nil {
+		return nil, err
+	}
 
-	return ShortURL, nil
+	return &model.CreateURLResponse{ShortURL: s.shortURL(url.Domain, url.ShortCode), Warning: warning}, nil
 }
 
-func (s *URLService) GetURL(ctx context.Context, shortCode string) (originalURL string, err error) {
+// GetURL 获取可以重定向的短链接，并为访客选出跳转地址，未到生效时间时返回 model.ErrURLNotActive
+// host为请求的域名，已验证的自定义域名只查找该域名下的短链接，其他域名都按默认域名处理
+// 定向规则优先于A/B版本，都没有命中时跳转到原始URL
+func (s *URLService) GetURL(ctx context.Context, host, shortCode string, visitor model.Visitor) (*model.URL, error) {
+	url, err := s.getURL(ctx, host, shortCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if url.IsExpired(visitor.Time) {
+		return nil, model.ErrURLNotFound
+	}
+
+	if !url.IsActive(visitor.Time) {
+		return nil, model.ErrURLNotActive
+	}
+
+	if destination, ok := s.matchRules(url, visitor); ok {
+		url.Destination = destination
+	} else if variant := chooseVariant(url, visitor); variant != nil {
+		url.Destination = variant.Destination
+		url.VariantID = variant.ID
+	} else {
+		url.Destination = url.OriginalURL
+	}
+
+	return url, nil
+}
+
+func (s *URLService) getURL(ctx context.Context, host, shortCode string) (*model.URL, error) {
+	host, err := s.resolveHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
 	// 先访问cache
-	originalURL, err = s.cache.GetURL(ctx, shortCode)
+	cached, err := s.cache.GetURL(ctx, model.URLKey(host, shortCode))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	if originalURL != "" {
-		return originalURL, nil
+	if cached != nil {
+		return cached, nil
 	}
 
 	// 访问数据库
-	row, err := s.querier.GetUrlByShortCode(ctx, shortCode)
+	row, err := s.querier.GetURLByHostAndShortCode(ctx, repo.GetURLByHostAndShortCodeParams{
+		ShortCode: shortCode,
+		Host:      host,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrURLNotFound
+	}
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	rules, err := s.listRules(ctx, row.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	variants, err := s.listVariants(ctx, row.ID)
+	if err != nil {
+		return nil, err
 	}
 
 	url := model.URL{
-		OriginalURL: row.OriginalUrl,
-		ShortCode:   row.ShortCode,
+		ID:            row.ID,
+		OriginalURL:   row.OriginalUrl,
+		ShortCode:     row.ShortCode,
+		Domain:        row.Host,
+		PasswordHash:  row.PasswordHash,
+		MaxClicks:     int(row.MaxClicks.Int32),
+		RedirectType:  row.RedirectType,
+		AlwaysPreview: row.AlwaysPreview,
+		CreatedAt:     row.CreatedAt,
+		ExpiredAt:     row.ExpiredAt,
+		Rules:         rules,
+		Variants:      variants,
+	}
+	if row.ActiveFrom.Valid {
+		url.ActiveFrom = &row.ActiveFrom.Time
 	}
 
 	// 存入缓存
 	if err := s.cache.SetURL(ctx, url); err != nil {
-		return "", err
+		return nil, err
+	}
+
+	return &url, nil
+}
+
+// PreviewURL 获取预览页展示的短链接信息，浏览量包括尚未同步到数据库的部分
+func (s *URLService) PreviewURL(ctx context.Context, url *model.URL) (*model.URLPreview, error) {
+	views, err := s.querier.GetViewsByID(ctx, url.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrURLNotFound
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := s.cache.GetViews(ctx, url.Key())
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &model.URLPreview{
+		ShortCode: url.ShortCode,
+		CreatedAt: url.CreatedAt,
+		ExpiredAt: url.ExpiredAt,
+		Views:     int(views) + pending,
+		Protected: url.IsProtected(),
+		Limited:   url.IsLimited(),
+	}
+	if !preview.Protected && !preview.Limited {
+		preview.Destination = url.Destination
+	}
+
+	return preview, nil
+}
 
-	return url.OriginalURL, nil
+// UnlockURL 校验受密码保护的短链接，同一IP输错次数过多时暂时拒绝校验
+func (s *URLService) UnlockURL(ctx context.Context, host, shortCode, password string, visitor model.Visitor) (*model.URL, error) {
+	url, err := s.GetURL(ctx, host, shortCode, visitor)
+	if err != nil {
+		return nil, err
+	}
+	if !url.IsProtected() {
+		return url, nil
+	}
+
+	// 先计数再校验，避免并发请求绕过次数限制
+	attempts, err := s.cache.IncrePasswordAttempts(ctx, url.Key(), visitor.IP)
+	if err != nil {
+		return nil, err
+	}
+	if attempts > s.maxPasswordTries {
+		return nil, model.ErrTooManyAttempts
+	}
+
+	if !s.passwordHasher.ComparePassword(url.PasswordHash, password) {
+		return nil, model.ErrURLPasswordIncorrect
+	}
+
+	if err := s.cache.DelPasswordAttempts(ctx, url.Key(), visitor.IP); err != nil {
+		return nil, err
+	}
+
+	return url, nil
+}
+
+// ConsumeClick 在数据库中原子地扣减限制次数的短链接的剩余次数，多实例部署时同样有效
+func (s *URLService) ConsumeClick(ctx context.Context, url *model.URL, click model.Click) error {
+	if !url.IsLimited() {
+		return nil
+	}
+
+	// 聊天软件的链接预览等请求既不能消耗次数，也不能拿到原始URL
+	if click.Prefetch || s.userAgentParser.Parse(click.UserAgent).IsBot {
+		return model.ErrBotNotAllowed
+	}
+
+	remaining, err := s.querier.ConsumeURLClick(ctx, url.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		if err := s.cache.DelURL(ctx, url.Key()); err != nil {
+			return err
+		}
+		return model.ErrURLClickLimitReached
+	}
+	if err != nil {
+		return err
+	}
+
+	// 次数用完后删除缓存，之后的请求在数据库中也查不到该链接
+	if remaining == 0 {
+		if err := s.cache.DelURL(ctx, url.Key()); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (s *URLService) getShortCode(ctx context.Context, n int) (string, error) {
+func (s *URLService) getShortCode(ctx context.Context, domainID sql.NullInt64, n int) (string, error) {
 	if n > 5 {
 		return "", errors.New("重试过多")
 	}
 	shortCode := s.shortCodeGenerator.GenerateShortCode()
 
-	isAvailable, err := s.querier.IsShortCodeAvailable(ctx, shortCode)
+	isAvailable, err := s.querier.IsShortCodeAvailable(ctx, repo.IsShortCodeAvailableParams{
+		ShortCode: shortCode,
+		DomainID:  domainID,
+	})
 	if err != nil {
 		return "", err
 	}
@@ -144,75 +464,350 @@ func (s *URLService) getShortCode(ctx context.Context, n int) (string, error) {
 		return shortCode, nil
 	}
 
-	return s.getShortCode(ctx, n+1)
+	return s.getShortCode(ctx, domainID, n+1)
 }
 
-func (s *URLService) GetURLs(ctx context.Context, req model.GetURLsRequest) (*model.GetURLsResponse, error) {
-	rows, err := s.querier.GetURLsByUserID(ctx, repo.GetURLsByUserIDParams{
-		UserID: int32(req.UserID),
-		Limit:  int32(req.Size),
-		Offset: int32((req.Page - 1) * req.Size),
+// execTx 在同一个事务中执行fn中的所有查询，fn返回错误时回滚
+func (s *URLService) execTx(ctx context.Context, fn func(q *repo.Queries) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(repo.New(tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%v, rollback: %w", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// urlRef 管理接口定位到的短链接，同一用户的短链接代码不会重复
+type urlRef struct {
+	id        int64
+	domain    string
+	shortCode string
+}
+
+// key 短链接在redis中的标识
+func (r urlRef) key() string {
+	return model.URLKey(r.domain, r.shortCode)
+}
+
+// getURLRef 按域名和代码查询属于该用户的短链接，domain为空表示默认域名
+func (s *URLService) getURLRef(ctx context.Context, shortCode, domain string, userID int) (urlRef, error) {
+	row, err := s.querier.GetURLByShortCodeAndUserID(ctx, repo.GetURLByShortCodeAndUserIDParams{
+		ShortCode: shortCode,
+		UserID:    int32(userID),
+		Host:      domain,
 	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return urlRef{}, model.ErrURLNotFound
+	}
+	if err != nil {
+		return urlRef{}, err
+	}
+
+	return urlRef{id: row.ID, domain: row.Host, shortCode: shortCode}, nil
+}
+
+// GetURLs 按游标分页获取用户的短链接，多查询一条用来判断是否还有下一页
+// 总数只在需要时单独查询，并按筛选条件缓存
+func (s *URLService) GetURLs(ctx context.Context, req model.GetURLsRequest) (*model.GetURLsResponse, error) {
+	filter, err := urlFilter(req)
 	if err != nil {
 		return nil, err
 	}
 
-	items := make([]model.FullURL, len(rows))
-	total := 0
+	sort := req.Sort
+	if sort == "" {
+		sort = model.URLSortCreatedAt
+	}
+	desc := req.Order != "asc"
 
-	for i := range rows {
-		row := &rows[i]
-		views, err := s.cache.GetViews(ctx, row.ShortCode)
+	page := urlPage{
+		filter: filter,
+		sort:   sort,
+		desc:   desc,
+		limit:  int32(req.Size) + 1,
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeURLCursor(req.Cursor, sort, desc)
 		if err != nil {
 			return nil, err
 		}
+		page.cursor = &cursor
+	} else if req.Page > 1 {
+		page.offset = int32((req.Page - 1) * req.Size)
+	}
 
-		row.Views += int32(views)
+	rows, err := s.listURLs(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp model.GetURLsResponse
+	if len(rows) > int(req.Size) {
+		rows = rows[:req.Size]
+		resp.NextCursor = newURLCursor(sort, desc, rows[len(rows)-1]).encode()
+	}
+
+	urlIDs := make([]int64, len(rows))
+	for i := range rows {
+		urlIDs[i] = rows[i].ID
+	}
+
+	tags, err := s.listURLTags(ctx, urlIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 整页的浏览量和访客数各用一次请求查询
+	keys := make([]string, len(rows))
+	for i := range rows {
+		keys[i] = model.URLKey(rows[i].Host, rows[i].ShortCode)
+	}
+
+	views, err := s.cache.GetViewsMulti(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	visitors, err := s.cache.CountVisitorsMulti(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]model.FullURL, len(rows))
+
+	for i := range rows {
+		row := &rows[i]
+		row.Views += int32(views[i])
 
 		items[i] = model.FullURL{
-			OriginalURL: row.OriginalUrl,
-			ShortURL:    fmt.Sprintf("%s/%s", s.baseURL, row.ShortCode),
-			ExpiredAt:   row.ExpiredAt,
-			IsCustom:    row.IsCustom,
-			Views:       uint(row.Views),
-			ID:          int(row.ID),
+			OriginalURL:    row.OriginalUrl,
+			RawURL:         row.RawUrl,
+			Warning:        urlWarning(row.OriginalUrl),
+			ShortURL:       s.shortURL(row.Host, row.ShortCode),
+			Title:          row.Title,
+			Notes:          row.Notes,
+			ExpiredAt:      row.ExpiredAt,
+			IsCustom:       row.IsCustom,
+			Views:          uint(row.Views),
+			UniqueVisitors: uint(visitors[i]),
+			ID:             int(row.ID),
+			Disabled:       row.Disabled,
+			Tags:           tags[row.ID],
+		}
+		if items[i].Tags == nil {
+			items[i].Tags = []model.Tag{}
+		}
+		if row.MaxClicks.Valid {
+			maxClicks := int(row.MaxClicks.Int32)
+			remaining := maxClicks - int(row.RedirectCount)
+			items[i].MaxClicks = &maxClicks
+			items[i].RemainingClicks = &remaining
 		}
-		total = int(row.Total)
 	}
+	resp.Items = items
 
-	resp := model.GetURLsResponse{
-		Items: items,
-		Total: total,
+	// 按页码翻页的旧客户端依赖总数计算页数
+	if req.WithTotal || req.Page > 0 {
+		total, err := s.countURLs(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		resp.Total = &total
 	}
 
 	return &resp, nil
 }
 
-func (s *URLService) DeleteURL(ctx context.Context, code string) error {
-	if err := s.querier.DeleteURLByShortCode(ctx, code); err != nil {
+// countURLs 获取符合筛选条件的短链接总数，先访问缓存
+func (s *URLService) countURLs(ctx context.Context, filter repo.CountURLsByUserIDParams) (int, error) {
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return 0, err
+	}
+	sum := sha256.Sum256(data)
+	key := hex.EncodeToString(sum[:])
+
+	userID := int(filter.UserID)
+	count, ok, err := s.cache.GetURLCount(ctx, userID, key)
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		return int(count), nil
+	}
+
+	count, err = s.querier.CountURLsByUserID(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.cache.SetURLCount(ctx, userID, key, count); err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
+
+// urlFilter 将列表的筛选条件转换为查询参数，未指定的条件为NULL
+func urlFilter(req model.GetURLsRequest) (repo.CountURLsByUserIDParams, error) {
+	params := repo.CountURLsByUserIDParams{
+		UserID: int32(req.UserID),
+		Tags:   uniqueTags(req.Tags),
+	}
+
+	if q := strings.TrimSpace(req.Q); q != "" {
+		params.Pattern = sql.NullString{String: "%" + escapeLike(q) + "%", Valid: true}
+	}
+	if req.Status != "" {
+		params.Status = sql.NullString{String: req.Status, Valid: true}
+	}
+	if req.IsCustom != nil {
+		params.IsCustom = sql.NullBool{Bool: *req.IsCustom, Valid: true}
+	}
+	if !req.CreatedFrom.IsZero() {
+		params.CreatedFrom = sql.NullTime{Time: req.CreatedFrom, Valid: true}
+	}
+	if !req.CreatedTo.IsZero() {
+		params.CreatedTo = sql.NullTime{Time: req.CreatedTo, Valid: true}
+	}
+	if params.CreatedFrom.Valid && params.CreatedTo.Valid && !params.CreatedFrom.Time.Before(params.CreatedTo.Time) {
+		return params, model.ErrInvalidFilter
+	}
+	if req.MinViews != nil {
+		params.MinViews = sql.NullInt32{Int32: int32(min(*req.MinViews, math.MaxInt32)), Valid: true}
+	}
+	if req.MaxViews != nil {
+		params.MaxViews = sql.NullInt32{Int32: int32(min(*req.MaxViews, math.MaxInt32)), Valid: true}
+	}
+	if params.MinViews.Valid && params.MaxViews.Valid && params.MinViews.Int32 > params.MaxViews.Int32 {
+		return params, model.ErrInvalidFilter
+	}
+
+	return params, nil
+}
+
+// escapeLike 转义LIKE中的通配符，使关键词按字面匹配
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// DeleteURL 将短链接移入回收站，短链接立即停止跳转，浏览量和访客数据保留到永久删除时
+func (s *URLService) DeleteURL(ctx context.Context, req model.DeleteURLRequest) error {
+	ref, err := s.getURLRef(ctx, req.Code, req.Domain, req.UserID)
+	if err != nil {
 		return err
 	}
 
-	if err := s.cache.DelURL(ctx, code); err != nil {
+	if err := s.querier.TrashURLByID(ctx, ref.id); err != nil {
 		return err
 	}
 
-	if err := s.cache.DelViews(ctx, code); err != nil {
+	if err := s.cache.DelURLCount(ctx, req.UserID); err != nil {
 		return err
 	}
 
-	return nil
+	return s.cache.DelURL(ctx, ref.key())
+}
+
+// UpdateURL 修改短链接，未传的字段保持不变，修改目标地址、标题或备注时记录新的历史版本
+func (s *URLService) UpdateURL(ctx context.Context, req model.UpdateURLRequest) error {
+	ref, err := s.getURLRef(ctx, req.Code, req.Domain, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	params := repo.UpdateURLSettingsByIDParams{
+		ID:              ref.id,
+		ClearActiveFrom: req.ClearActiveFrom,
+	}
+	if req.ExpiredAt != nil {
+		params.ExpiredAt = sql.NullTime{Time: req.ExpiredAt.UTC(), Valid: true}
+	}
+	if req.ActiveFrom != nil {
+		params.ActiveFrom = sql.NullTime{Time: req.ActiveFrom.UTC(), Valid: true}
+	}
+	if req.RedirectType != nil {
+		params.RedirectType = sql.NullString{String: *req.RedirectType, Valid: true}
+	}
+	if req.AlwaysPreview != nil {
+		params.AlwaysPreview = sql.NullBool{Bool: *req.AlwaysPreview, Valid: true}
+	}
+
+	content := urlContent{
+		title: req.Title,
+		notes: req.Notes,
+	}
+	if req.OriginalURL != nil {
+		originalURL, err := s.urlNormalizer.Normalize(*req.OriginalURL)
+		if err != nil {
+			return model.ErrInvalidURL
+		}
+		content.originalURL = &originalURL
+		content.rawURL = req.OriginalURL
+	}
+
+	if err := s.execTx(ctx, func(q *repo.Queries) error {
+		if err := q.UpdateURLSettingsByID(ctx, params); err != nil {
+			return err
+		}
+
+		return updateContent(ctx, q, ref.id, req.UserID, content, sql.NullInt64{})
+	}); err != nil {
+		return err
+	}
+
+	if err := s.cache.DelURLCount(ctx, req.UserID); err != nil {
+		return err
+	}
+
+	return s.cache.DelURL(ctx, ref.key())
 }
 
-func (s *URLService) UpdateURLDuration(ctx context.Context, req model.UpdateURLDurationReq) error {
-	return s.querier.UpdateURLExpiredByShortCode(ctx, repo.UpdateURLExpiredByShortCodeParams{
-		ExpiredAt: req.ExpiredAt,
-		ShortCode: req.Code,
+// RecordClick 记录一次点击，机器人和预加载请求不计入浏览量和独立访客
+func (s *URLService) RecordClick(ctx context.Context, click model.Click) error {
+	country, city := s.geoLocator.Lookup(click.IP)
+	ua := s.userAgentParser.Parse(click.UserAgent)
+	isBot := ua.IsBot || click.Prefetch
+
+	if !isBot {
+		if err := s.cache.IncreViews(ctx, click.Key); err != nil {
+			return err
+		}
+
+		if err := s.cache.AddVisitor(ctx, click.Key, visitorFingerprint(click.Visitor), click.Time); err != nil {
+			return err
+		}
+	}
+
+	return s.querier.CreateClick(ctx, repo.CreateClickParams{
+		UrlID:       click.URLID,
+		Destination: click.Destination,
+		Referrer:    click.Referrer,
+		UserAgent:   click.UserAgent,
+		Ip:          click.IP,
+		Country:     country,
+		City:        city,
+		Device:      ua.Device,
+		Os:          ua.OS,
+		Browser:     ua.Browser,
+		IsBot:       isBot,
+		ClickedAt:   click.Time,
+		VariantID:   sql.NullInt64{Int64: click.VariantID, Valid: click.VariantID != 0},
 	})
 }
 
-func (s *URLService) IncreViews(ctx context.Context, shortCode string) error {
-	return s.cache.IncreViews(ctx, shortCode)
+// visitorFingerprint 使用IP和User-Agent的哈希值区分访客，避免在缓存中保存原始IP
+func visitorFingerprint(visitor model.Visitor) string {
+	sum := sha256.Sum256([]byte(visitor.IP + "|" + visitor.UserAgent))
+	return hex.EncodeToString(sum[:16])
 }
 
 func (s *URLService) SyncViewsToDB(ctx context.Context) error {
@@ -239,11 +834,16 @@ func (s *URLService) SyncViewsToDB(ctx context.Context) error {
 				return err
 			}
 
-			shortCode := strings.Split(key, ":")[1]
+			// 自定义域名下的短链接的标识为 域名/短链接代码
+			host, shortCode, ok := strings.Cut(key, "/")
+			if !ok {
+				host, shortCode = "", host
+			}
 
-			if err := s.querier.UpdateViewsByShortCode(ctx, repo.UpdateViewsByShortCodeParams{
+			if err := s.querier.UpdateViewsByHostAndShortCode(ctx, repo.UpdateViewsByHostAndShortCodeParams{
 				Views:     int32(views),
 				ShortCode: shortCode,
+				Host:      host,
 			}); err != nil {
 				return err
 			}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aeilang/urlshortener/config"
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/aeilang/urlshortener/internal/repo"
+)
+
+func TestUnlockURL(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		passwords []string
+		wantErrs  []error
+	}{
+		{
+			name:      "correct password",
+			passwords: []string{"secret"},
+			wantErrs:  []error{nil},
+		},
+		{
+			name:      "wrong then correct",
+			passwords: []string{"x", "y", "secret"},
+			wantErrs:  []error{model.ErrURLPasswordIncorrect, model.ErrURLPasswordIncorrect, nil},
+		},
+		{
+			name:      "locked after max attempts",
+			passwords: []string{"x", "y", "z", "secret"},
+			wantErrs:  []error{model.ErrURLPasswordIncorrect, model.ErrURLPasswordIncorrect, model.ErrURLPasswordIncorrect, model.ErrTooManyAttempts},
+		},
+		{
+			name:      "success resets attempts",
+			passwords: []string{"x", "y", "secret", "x", "y", "secret"},
+			wantErrs:  []error{model.ErrURLPasswordIncorrect, model.ErrURLPasswordIncorrect, nil, model.ErrURLPasswordIncorrect, model.ErrURLPasswordIncorrect, nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := newFakeCache()
+			cache.urls["abcd"] = model.URL{
+				ID:           1,
+				OriginalURL:  "https://example.com/",
+				ShortCode:    "abcd",
+				PasswordHash: "hashed:secret",
+				ExpiredAt:    now.Add(time.Hour),
+			}
+			s := &URLService{
+				cache:            cache,
+				passwordHasher:   fakeHasher{},
+				baseHost:         "s.example",
+				maxPasswordTries: 3,
+			}
+			visitor := model.Visitor{IP: "203.0.113.1", Time: now}
+
+			for i, password := range tt.passwords {
+				url, err := s.UnlockURL(context.Background(), "s.example", "abcd", password, visitor)
+				if !errors.Is(err, tt.wantErrs[i]) {
+					t.Fatalf("attempt %d: UnlockURL() error = %v, want %v", i+1, err, tt.wantErrs[i])
+				}
+				if err == nil && url.Destination != "https://example.com/" {
+					t.Errorf("attempt %d: Destination = %q, want %q", i+1, url.Destination, "https://example.com/")
+				}
+			}
+		})
+	}
+}
+
+func TestUnlockURLWithoutPassword(t *testing.T) {
+	cache := newFakeCache()
+	cache.urls["abcd"] = model.URL{ID: 1, OriginalURL: "https://example.com/", ShortCode: "abcd"}
+	s := &URLService{cache: cache, passwordHasher: fakeHasher{}, maxPasswordTries: 3}
+
+	if _, err := s.UnlockURL(context.Background(), "", "abcd", "", model.Visitor{IP: "203.0.113.1", Time: time.Now()}); err != nil {
+		t.Fatalf("UnlockURL() error = %v", err)
+	}
+	if len(cache.attempts) != 0 {
+		t.Errorf("attempts = %v, want none for an unprotected link", cache.attempts)
+	}
+}
+
+func TestNewURLServicePasswordDefault(t *testing.T) {
+	s := NewURLService(nil, nil, nil, nil, nil, nil, nil, nil, nil, config.AppConfig{BaseURL: "https://s.example"})
+
+	if s.maxPasswordTries != defaultPasswordMaxAttempts {
+		t.Errorf("maxPasswordTries = %d, want %d", s.maxPasswordTries, defaultPasswordMaxAttempts)
+	}
+}
+
+func TestSyncViewsToDB(t *testing.T) {
+	cache := newFakeCache()
+	cache.views["abcd"] = 3
+	cache.views["go.example.com/abcd"] = 5
+	cache.views["zero"] = 0
+	querier := &fakeQuerier{}
+	s := &URLService{querier: querier, cache: cache}
+
+	if err := s.SyncViewsToDB(context.Background()); err != nil {
+		t.Fatalf("SyncViewsToDB() error = %v", err)
+	}
+
+	want := []repo.UpdateViewsByHostAndShortCodeParams{
+		{Views: 3, ShortCode: "abcd"},
+		{Views: 5, ShortCode: "abcd", Host: "go.example.com"},
+	}
+	if !reflect.DeepEqual(querier.viewUpdates, want) {
+		t.Errorf("view updates = %+v, want %+v", querier.viewUpdates, want)
+	}
+	if _, ok := cache.views["abcd"]; ok {
+		t.Errorf("views of %q were not removed from cache", "abcd")
+	}
+	if _, ok := cache.views["go.example.com/abcd"]; ok {
+		t.Errorf("views of %q were not removed from cache", "go.example.com/abcd")
+	}
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"hash/fnv"
+	"time"
+
+	"github.com/aeilang/urlshortener/internal/model"
+	"github.com/aeilang/urlshortener/internal/repo"
+)
+
+// GetVariants 获取短链接的所有A/B版本
+func (s *URLService) GetVariants(ctx context.Context, req model.GetVariantsRequest) (*model.GetVariantsResponse, error) {
+	ref, err := s.getURLRef(ctx, req.Code, req.Domain, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	variants, err := s.listVariants(ctx, ref.id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.GetVariantsResponse{Items: variants}, nil
+}
+
+// CreateVariant 为短链接添加一个A/B版本
+func (s *URLService) CreateVariant(ctx context.Context, req model.CreateVariantRequest) (*model.Variant, error) {
+	ref, err := s.getURLRef(ctx, req.Code, req.Domain, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := s.querier.CreateVariant(ctx, repo.CreateVariantParams{
+		UrlID:       ref.id,
+		Destination: req.Destination,
+		Weight:      int32(req.Weight),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.DelURL(ctx, ref.key()); err != nil {
+		return nil, err
+	}
+
+	variant := toVariant(row)
+	return &variant, nil
+}
+
+// UpdateVariant 修改A/B版本的跳转地址和权重，已分配到该版本的访客不受影响
+func (s *URLService) UpdateVariant(ctx context.Context, req model.UpdateVariantRequest) (*model.Variant, error) {
+	ref, err := s.getURLRef(ctx, req.Code, req.Domain, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := s.querier.UpdateVariant(ctx, repo.UpdateVariantParams{
+		Destination: req.Destination,
+		Weight:      int32(req.Weight),
+		ID:          req.ID,
+		UrlID:       ref.id,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.ErrVariantNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.DelURL(ctx, ref.key()); err != nil {
+		return nil, err
+	}
+
+	variant := toVariant(row)
+	return &variant, nil
+}
+
+// DeleteVariant 删除A/B版本，已分配到该版本的访客会被重新分配
+func (s *URLService) DeleteVariant(ctx context.Context, req model.DeleteVariantRequest) error {
+	ref, err := s.getURLRef(ctx, req.Code, req.Domain, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	n, err := s.querier.DeleteVariant(ctx, repo.DeleteVariantParams{
+		ID:    req.ID,
+		UrlID: ref.id,
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return model.ErrVariantNotFound
+	}
+
+	return s.cache.DelURL(ctx, ref.key())
+}
+
+func (s *URLService) listVariants(ctx context.Context, urlID int64) ([]model.Variant, error) {
+	rows, err := s.querier.ListVariantsByURLID(ctx, urlID)
+	if err != nil {
+		return nil, err
+	}
+
+	variants := make([]model.Variant, len(rows))
+	for i, row := range rows {
+		variants[i] = toVariant(row)
+	}
+
+	return variants, nil
+}
+
+func toVariant(row repo.UrlVariant) model.Variant {
+	return model.Variant{
+		ID:          row.ID,
+		Destination: row.Destination,
+		Weight:      int(row.Weight),
+	}
+}
+
+// variantStats 统计每个A/B版本的点击次数，短链接没有A/B版本时返回nil
+func (s *URLService) variantStats(ctx context.Context, urlID int64, from, to time.Time) ([]model.VariantStats, error) {
+	variants, err := s.listVariants(ctx, urlID)
+	if err != nil || len(variants) == 0 {
+		return nil, err
+	}
+
+	rows, err := s.querier.CountClicksByVariant(ctx, repo.CountClicksByVariantParams{
+		UrlID:   urlID,
+		StartAt: from,
+		EndAt:   to,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int64]repo.CountClicksByVariantRow, len(rows))
+	for _, row := range rows {
+		counts[row.VariantID] = row
+	}
+
+	stats := make([]model.VariantStats, len(variants))
+	for i, v := range variants {
+		stats[i] = model.VariantStats{
+			ID:          v.ID,
+			Destination: v.Destination,
+			Weight:      v.Weight,
+			Clicks:      counts[v.ID].Clicks,
+			BotClicks:   counts[v.ID].BotClicks,
+		}
+	}
+
+	return stats, nil
+}
+
+// chooseVariant 按权重为访客分配A/B版本，没有版本时返回nil
+// cookie中记录的版本仍然存在时继续使用，否则根据访客指纹的哈希值分配，同一访客总是看到同一版本
+func chooseVariant(url *model.URL, visitor model.Visitor) *model.Variant {
+	if len(url.Variants) == 0 {
+		return nil
+	}
+
+	total := 0
+	for i := range url.Variants {
+		if url.Variants[i].ID == visitor.StickyVariant {
+			return &url.Variants[i]
+		}
+		total += url.Variants[i].Weight
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(url.ShortCode + "|" + visitorFingerprint(visitor)))
+	n := int(h.Sum64() % uint64(total))
+
+	for i := range url.Variants {
+		n -= url.Variants[i].Weight
+		if n < 0 {
+			return &url.Variants[i]
+		}
+	}
+
+	return &url.Variants[len(url.Variants)-1]
+}
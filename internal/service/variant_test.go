@@ -0,0 +1,86 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aeilang/urlshortener/internal/model"
+)
+
+func TestChooseVariant(t *testing.T) {
+	variants := []model.Variant{
+		{ID: 1, Destination: "https://a.example.com/", Weight: 1},
+		{ID: 2, Destination: "https://b.example.com/", Weight: 3},
+	}
+
+	tests := []struct {
+		name    string
+		url     *model.URL
+		visitor model.Visitor
+		want    int64
+	}{
+		{
+			name:    "no variants",
+			url:     &model.URL{ShortCode: "abcd"},
+			visitor: model.Visitor{IP: "203.0.113.1"},
+			want:    0,
+		},
+		{
+			name:    "sticky variant",
+			url:     &model.URL{ShortCode: "abcd", Variants: variants},
+			visitor: model.Visitor{IP: "203.0.113.1", StickyVariant: 2},
+			want:    2,
+		},
+		{
+			name:    "single variant",
+			url:     &model.URL{ShortCode: "abcd", Variants: variants[:1]},
+			visitor: model.Visitor{IP: "203.0.113.1", StickyVariant: 2},
+			want:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got int64
+			if v := chooseVariant(tt.url, tt.visitor); v != nil {
+				got = v.ID
+			}
+			if got != tt.want {
+				t.Errorf("chooseVariant() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChooseVariantStable(t *testing.T) {
+	url := &model.URL{ShortCode: "abcd", Variants: []model.Variant{
+		{ID: 1, Weight: 1},
+		{ID: 2, Weight: 3},
+	}}
+	visitor := model.Visitor{IP: "203.0.113.1", UserAgent: "Mozilla/5.0"}
+
+	first := chooseVariant(url, visitor).ID
+	for i := 0; i < 10; i++ {
+		if got := chooseVariant(url, visitor).ID; got != first {
+			t.Fatalf("chooseVariant() = %d, want %d for the same visitor", got, first)
+		}
+	}
+}
+
+func TestChooseVariantWeights(t *testing.T) {
+	url := &model.URL{ShortCode: "abcd", Variants: []model.Variant{
+		{ID: 1, Weight: 1},
+		{ID: 2, Weight: 3},
+	}}
+
+	counts := map[int64]int{}
+	for i := 0; i < 4000; i++ {
+		visitor := model.Visitor{IP: fmt.Sprintf("10.0.%d.%d", i/256, i%256)}
+		counts[chooseVariant(url, visitor).ID]++
+	}
+
+	// 权重1:3，允许一定的误差
+	if counts[1] < 800 || counts[1] > 1200 {
+		t.Errorf("variant 1 chosen %d times out of 4000, want about 1000", counts[1])
+	}
+}
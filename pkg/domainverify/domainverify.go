@@ -0,0 +1,111 @@
+package domainverify
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/aeilang/urlshortener/config"
+)
+
+var ErrRecordNotFound = errors.New("未找到匹配的TXT记录")
+
+const (
+	defaultRecordPrefix = "_urlshortener"
+	recordValuePrefix   = "urlshortener-verification="
+	dialTimeout         = 5 * time.Second
+)
+
+// Resolver 查询域名的TXT记录，net.Resolver 实现了该接口
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// NewResolver 未配置DNS服务器时使用系统的解析器，否则直接向配置的服务器查询
+func NewResolver(cfg config.DomainConfig) Resolver {
+	if cfg.DNSServer == "" {
+		return net.DefaultResolver
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			d := net.Dialer{Timeout: dialTimeout}
+			return d.DialContext(ctx, network, cfg.DNSServer)
+		},
+	}
+}
+
+// StubResolver 从内存中返回TXT记录，用于测试和本地开发
+type StubResolver map[string][]string
+
+func (r StubResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	records, ok := r[strings.ToLower(strings.TrimSuffix(name, "."))]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+// Verifier 通过TXT记录验证用户对域名的所有权
+// 用户需要添加一条名为 RecordName(host)、值为 RecordValue(token) 的TXT记录
+type Verifier struct {
+	resolver Resolver
+	prefix   string
+}
+
+func NewVerifier(cfg config.DomainConfig, resolver Resolver) *Verifier {
+	prefix := cfg.RecordPrefix
+	if prefix == "" {
+		prefix = defaultRecordPrefix
+	}
+
+	return &Verifier{
+		resolver: resolver,
+		prefix:   prefix,
+	}
+}
+
+// NewToken 生成随机的验证值
+func (v *Verifier) NewToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// RecordName 返回需要添加的TXT记录的名称
+func (v *Verifier) RecordName(host string) string {
+	return v.prefix + "." + host
+}
+
+// RecordValue 返回需要添加的TXT记录的值
+func (v *Verifier) RecordValue(token string) string {
+	return recordValuePrefix + token
+}
+
+// Verify 检查域名是否存在匹配的TXT记录，记录不存在时返回 ErrRecordNotFound
+func (v *Verifier) Verify(ctx context.Context, host, token string) error {
+	records, err := v.resolver.LookupTXT(ctx, v.RecordName(host))
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return ErrRecordNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	want := v.RecordValue(token)
+	for _, record := range records {
+		if strings.TrimSpace(record) == want {
+			return nil
+		}
+	}
+
+	return ErrRecordNotFound
+}
@@ -0,0 +1,59 @@
+package domainverify
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aeilang/urlshortener/config"
+)
+
+func TestVerify(t *testing.T) {
+	resolver := StubResolver{
+		"_urlshortener.go.example.com": {"v=spf1 -all", "urlshortener-verification=abc123"},
+		"_urlshortener.other.example":  {"urlshortener-verification=zzz"},
+	}
+	v := NewVerifier(config.DomainConfig{}, resolver)
+
+	tests := []struct {
+		name  string
+		host  string
+		token string
+		want  error
+	}{
+		{"matching record", "go.example.com", "abc123", nil},
+		{"wrong token", "other.example", "abc123", ErrRecordNotFound},
+		{"no record", "missing.example", "abc123", ErrRecordNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := v.Verify(context.Background(), tt.host, tt.token); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecord(t *testing.T) {
+	v := NewVerifier(config.DomainConfig{RecordPrefix: "_verify"}, StubResolver{})
+
+	if got := v.RecordName("go.example.com"); got != "_verify.go.example.com" {
+		t.Errorf("RecordName = %q", got)
+	}
+	if got := v.RecordValue("abc123"); got != "urlshortener-verification=abc123" {
+		t.Errorf("RecordValue = %q", got)
+	}
+
+	a, err := v.NewToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := v.NewToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a) != 32 || a == b {
+		t.Errorf("NewToken = %q, %q", a, b)
+	}
+}
@@ -0,0 +1,55 @@
+package geoip
+
+import (
+	"net"
+
+	"github.com/aeilang/urlshortener/config"
+	"github.com/oschwald/geoip2-golang"
+)
+
+// GeoIP 使用本地的MMDB文件解析IP所在的国家和城市，不依赖任何外部服务
+type GeoIP struct {
+	reader *geoip2.Reader
+}
+
+// NewGeoIP 打开配置的MMDB文件，未配置路径时返回不做解析的GeoIP
+func NewGeoIP(cfg config.GeoIPConfig) (*GeoIP, error) {
+	if cfg.DBPath == "" {
+		return &GeoIP{}, nil
+	}
+
+	reader, err := geoip2.Open(cfg.DBPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GeoIP{
+		reader: reader,
+	}, nil
+}
+
+// Lookup 返回国家的ISO代码和城市的英文名称，解析失败时返回空字符串
+func (g *GeoIP) Lookup(ip string) (country, city string) {
+	if g.reader == nil {
+		return "", ""
+	}
+
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return "", ""
+	}
+
+	record, err := g.reader.City(parsed)
+	if err != nil {
+		return "", ""
+	}
+
+	return record.Country.IsoCode, record.City.Names["en"]
+}
+
+func (g *GeoIP) Close() error {
+	if g.reader == nil {
+		return nil
+	}
+	return g.reader.Close()
+}
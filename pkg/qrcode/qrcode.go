@@ -0,0 +1,266 @@
+package qrcode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"strconv"
+	"strings"
+
+	_ "image/jpeg"
+
+	"github.com/aeilang/urlshortener/config"
+	qr "github.com/skip2/go-qrcode"
+)
+
+var ErrLogoNotConfigured = errors.New("未配置二维码logo")
+var ErrSizeTooSmall = errors.New("二维码尺寸过小")
+
+var levels = map[string]qr.RecoveryLevel{
+	"L": qr.Low,
+	"M": qr.Medium,
+	"Q": qr.High,
+	"H": qr.Highest,
+}
+
+// logoRatio logo的边长占二维码(不含留白)边长的比例，H级纠错可以容忍该面积的遮挡
+const logoRatio = 0.2
+
+// Options 二维码的样式
+type Options struct {
+	Size       int    // 图片边长(像素)
+	Margin     int    // 四周留白的宽度(模块数)
+	Level      string // 纠错等级 L/M/Q/H
+	Foreground color.RGBA
+	Background color.RGBA
+	Logo       bool // 在中间绘制配置的logo，会将纠错等级提高到H
+}
+
+// Generator 生成PNG或SVG格式的二维码
+type Generator struct {
+	logo    image.Image
+	logoPNG string // base64编码的logo，用于嵌入SVG
+}
+
+// NewGenerator 加载配置的logo文件(PNG/JPEG)，未配置路径时不支持绘制logo
+func NewGenerator(cfg config.QRCodeConfig) (*Generator, error) {
+	if cfg.LogoPath == "" {
+		return &Generator{}, nil
+	}
+
+	f, err := os.Open(cfg.LogoPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	logo, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("解析二维码logo失败: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, logo); err != nil {
+		return nil, err
+	}
+
+	return &Generator{
+		logo:    logo,
+		logoPNG: base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}, nil
+}
+
+// ParseHexColor 解析 RRGGBB 格式的颜色
+func ParseHexColor(s string) (color.RGBA, error) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 6 {
+		return color.RGBA{}, fmt.Errorf("颜色格式应为RRGGBB: %q", s)
+	}
+
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("颜色格式应为RRGGBB: %q", s)
+	}
+
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+// layout 二维码的模块矩阵及其在图片中的位置
+type layout struct {
+	bitmap [][]bool
+	scale  int // 每个模块的像素数
+	offset int // 第一个模块左上角的像素坐标
+}
+
+func (g *Generator) layout(content string, opts Options) (*layout, error) {
+	if opts.Logo && g.logo == nil {
+		return nil, ErrLogoNotConfigured
+	}
+
+	level, ok := levels[opts.Level]
+	if !ok {
+		return nil, fmt.Errorf("不支持的纠错等级: %q", opts.Level)
+	}
+	if opts.Logo {
+		level = qr.Highest
+	}
+
+	code, err := qr.New(content, level)
+	if err != nil {
+		return nil, err
+	}
+	code.DisableBorder = true
+	bitmap := code.Bitmap()
+
+	modules := len(bitmap) + 2*opts.Margin
+	scale := opts.Size / modules
+	if scale < 1 {
+		return nil, ErrSizeTooSmall
+	}
+
+	// 尺寸不能整除时多出的像素平均分到两侧
+	return &layout{
+		bitmap: bitmap,
+		scale:  scale,
+		offset: (opts.Size-scale*modules)/2 + opts.Margin*scale,
+	}, nil
+}
+
+// logoRect 返回logo在图片中的区域，保持logo的宽高比
+func (l *layout) logoRect(logo image.Image) image.Rectangle {
+	width := l.scale * len(l.bitmap)
+	box := int(float64(width) * logoRatio)
+
+	b := logo.Bounds()
+	w, h := box, box
+	if b.Dx() > b.Dy() {
+		h = box * b.Dy() / b.Dx()
+	} else {
+		w = box * b.Dx() / b.Dy()
+	}
+
+	center := l.offset + width/2
+	return image.Rect(center-w/2, center-h/2, center-w/2+w, center-h/2+h)
+}
+
+// PNG 生成PNG格式的二维码
+func (g *Generator) PNG(content string, opts Options) ([]byte, error) {
+	l, err := g.layout(content, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, opts.Size, opts.Size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: opts.Background}, image.Point{}, draw.Src)
+
+	fg := &image.Uniform{C: opts.Foreground}
+	for y, row := range l.bitmap {
+		for x, set := range row {
+			if !set {
+				continue
+			}
+			r := image.Rect(0, 0, l.scale, l.scale).Add(image.Pt(l.offset+x*l.scale, l.offset+y*l.scale))
+			draw.Draw(img, r, fg, image.Point{}, draw.Src)
+		}
+	}
+
+	if opts.Logo {
+		r := l.logoRect(g.logo)
+		pad := l.scale
+		draw.Draw(img, r.Inset(-pad), &image.Uniform{C: opts.Background}, image.Point{}, draw.Src)
+		drawScaled(img, r, g.logo)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// SVG 生成SVG格式的二维码，每一行连续的模块合并为一个矩形
+func (g *Generator) SVG(content string, opts Options) ([]byte, error) {
+	l, err := g.layout(content, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		opts.Size, opts.Size, opts.Size, opts.Size)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="%s"/>`, opts.Size, opts.Size, hexColor(opts.Background))
+
+	fmt.Fprintf(&buf, `<path fill="%s" d="`, hexColor(opts.Foreground))
+	for y, row := range l.bitmap {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv%dh-%dz",
+				l.offset+start*l.scale, l.offset+y*l.scale, (x-start)*l.scale, l.scale, (x-start)*l.scale)
+		}
+	}
+	buf.WriteString(`"/>`)
+
+	if opts.Logo {
+		r := l.logoRect(g.logo)
+		pad := r.Inset(-l.scale)
+		fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`,
+			pad.Min.X, pad.Min.Y, pad.Dx(), pad.Dy(), hexColor(opts.Background))
+		fmt.Fprintf(&buf, `<image x="%d" y="%d" width="%d" height="%d" href="data:image/png;base64,%s"/>`,
+			r.Min.X, r.Min.Y, r.Dx(), r.Dy(), g.logoPNG)
+	}
+
+	buf.WriteString(`</svg>`)
+
+	return buf.Bytes(), nil
+}
+
+// drawScaled 使用最近邻插值将src缩放后绘制到dst的r区域
+func drawScaled(dst draw.Image, r image.Rectangle, src image.Image) {
+	sb := src.Bounds()
+	if r.Dx() <= 0 || r.Dy() <= 0 {
+		return
+	}
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		sy := sb.Min.Y + (y-r.Min.Y)*sb.Dy()/r.Dy()
+		for x := r.Min.X; x < r.Max.X; x++ {
+			sx := sb.Min.X + (x-r.Min.X)*sb.Dx()/r.Dx()
+			c := color.RGBAModel.Convert(src.At(sx, sy)).(color.RGBA)
+			if c.A == 0xff {
+				dst.Set(x, y, c)
+				continue
+			}
+			// 半透明的像素与背景混合
+			bg := color.RGBAModel.Convert(dst.At(x, y)).(color.RGBA)
+			dst.Set(x, y, blend(c, bg))
+		}
+	}
+}
+
+// blend 将预乘alpha的前景色叠加到背景色上
+func blend(fg, bg color.RGBA) color.RGBA {
+	a := 0xff - uint32(fg.A)
+	return color.RGBA{
+		R: uint8(uint32(fg.R) + uint32(bg.R)*a/0xff),
+		G: uint8(uint32(fg.G) + uint32(bg.G)*a/0xff),
+		B: uint8(uint32(fg.B) + uint32(bg.B)*a/0xff),
+		A: 0xff,
+	}
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,156 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aeilang/urlshortener/config"
+)
+
+var (
+	black = color.RGBA{A: 0xff}
+	white = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	red   = color.RGBA{R: 0xff, A: 0xff}
+)
+
+func TestPNG(t *testing.T) {
+	g, err := NewGenerator(config.QRCodeConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := g.PNG("http://localhost:8080/abc123", Options{
+		Size: 300, Margin: 4, Level: "M", Foreground: black, Background: white,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 300 || img.Bounds().Dy() != 300 {
+		t.Fatalf("size = %v, want 300x300", img.Bounds())
+	}
+	if c := color.RGBAModel.Convert(img.At(0, 0)); c != white {
+		t.Errorf("margin color = %v, want %v", c, white)
+	}
+}
+
+func TestSVG(t *testing.T) {
+	g, err := NewGenerator(config.QRCodeConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := g.SVG("http://localhost:8080/abc123", Options{
+		Size: 256, Margin: 0, Level: "H", Foreground: red, Background: white,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svg := string(data)
+	for _, want := range []string{`width="256"`, `fill="#ff0000"`, `fill="#ffffff"`} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("svg does not contain %s", want)
+		}
+	}
+}
+
+func TestOptionsErrors(t *testing.T) {
+	g, err := NewGenerator(config.QRCodeConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want error
+	}{
+		{"logo not configured", Options{Size: 256, Level: "M", Logo: true}, ErrLogoNotConfigured},
+		{"size too small", Options{Size: 10, Margin: 4, Level: "M"}, ErrSizeTooSmall},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := g.PNG("http://localhost:8080/abc123", tt.opts); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogo(t *testing.T) {
+	logo := image.NewRGBA(image.Rect(0, 0, 20, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 20; x++ {
+			logo.Set(x, y, red)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "logo.png")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, logo); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	g, err := NewGenerator(config.QRCodeConfig{LogoPath: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := g.PNG("http://localhost:8080/abc123", Options{
+		Size: 400, Margin: 4, Level: "L", Foreground: black, Background: white, Logo: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := color.RGBAModel.Convert(img.At(200, 200)); c != red {
+		t.Errorf("center color = %v, want logo color %v", c, red)
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    color.RGBA
+		wantErr bool
+	}{
+		{"000000", black, false},
+		{"#ff0000", red, false},
+		{"FFFFFF", white, false},
+		{"fff", color.RGBA{}, true},
+		{"zzzzzz", color.RGBA{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseHexColor(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package urlnorm
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/aeilang/urlshortener/config"
+	"golang.org/x/net/idna"
+)
+
+var ErrInvalidURL = errors.New("URL格式不正确")
+
+// 未配置时去掉的跟踪参数，以*结尾的按前缀匹配
+var defaultStripParams = []string{
+	"utm_*", "fbclid", "gclid", "dclid", "gbraid", "wbraid", "msclkid",
+	"yclid", "mc_cid", "mc_eid", "igshid", "_hsenc", "_hsmi", "spm",
+}
+
+// 转换国际化域名并转为小写，允许主机名中出现下划线
+var profile = idna.New(idna.MapForLookup(), idna.BidiRule(), idna.StrictDomainName(false))
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+type Normalizer struct {
+	stripParams   map[string]bool
+	stripPrefixes []string
+	sortParams    bool
+}
+
+// NewNormalizer 配置的参数会替换默认的跟踪参数，匹配时忽略大小写
+func NewNormalizer(cfg config.NormalizeConfig) *Normalizer {
+	params := defaultStripParams
+	if len(cfg.StripParams) > 0 {
+		params = cfg.StripParams
+	}
+
+	n := &Normalizer{
+		stripParams: make(map[string]bool, len(params)),
+		sortParams:  cfg.SortParams,
+	}
+	for _, param := range params {
+		param = strings.ToLower(param)
+		if prefix, ok := strings.CutSuffix(param, "*"); ok {
+			n.stripPrefixes = append(n.stripPrefixes, prefix)
+		} else {
+			n.stripParams[param] = true
+		}
+	}
+
+	return n
+}
+
+// Normalize 只接受http和https的URL，返回规范形式: 协议和域名转为小写，国际化域名转为punycode，
+// 去掉默认端口和跟踪参数，按配置对其余查询参数排序。路径和片段保持不变
+func (n *Normalizer) Normalize(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", ErrInvalidURL
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if _, ok := defaultPorts[u.Scheme]; !ok {
+		return "", ErrInvalidURL
+	}
+
+	host, port := u.Hostname(), u.Port()
+	if net.ParseIP(host) == nil {
+		host, err = profile.ToASCII(strings.TrimSuffix(host, "."))
+		if err != nil || host == "" {
+			return "", ErrInvalidURL
+		}
+	}
+	if port == defaultPorts[u.Scheme] {
+		port = ""
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	u.Host = host
+	if port != "" {
+		u.Host += ":" + port
+	}
+
+	if u.RawQuery != "" {
+		u.RawQuery = n.cleanQuery(u.RawQuery)
+	}
+
+	return u.String(), nil
+}
+
+// cleanQuery 只在需要时重新编码查询参数，避免改变没有跟踪参数的URL
+func (n *Normalizer) cleanQuery(rawQuery string) string {
+	parts := strings.Split(rawQuery, "&")
+	kept := parts[:0]
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+		key, _, _ := strings.Cut(part, "=")
+		if name, err := url.QueryUnescape(key); err == nil && n.isTracking(name) {
+			continue
+		}
+		kept = append(kept, part)
+	}
+
+	if n.sortParams {
+		// 参数名相同时保持原有顺序
+		sort.SliceStable(kept, func(i, j int) bool {
+			ki, _, _ := strings.Cut(kept[i], "=")
+			kj, _, _ := strings.Cut(kept[j], "=")
+			return ki < kj
+		})
+	}
+
+	return strings.Join(kept, "&")
+}
+
+func (n *Normalizer) isTracking(name string) bool {
+	name = strings.ToLower(name)
+	if n.stripParams[name] {
+		return true
+	}
+	for _, prefix := range n.stripPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// 中日韩文字通常混合使用，视为同一种文字
+var scriptGroups = []struct {
+	name   string
+	tables []*unicode.RangeTable
+}{
+	{"latin", []*unicode.RangeTable{unicode.Latin}},
+	{"cyrillic", []*unicode.RangeTable{unicode.Cyrillic}},
+	{"greek", []*unicode.RangeTable{unicode.Greek}},
+	{"armenian", []*unicode.RangeTable{unicode.Armenian}},
+	{"cjk", []*unicode.RangeTable{unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul}},
+	{"arabic", []*unicode.RangeTable{unicode.Arabic}},
+	{"hebrew", []*unicode.RangeTable{unicode.Hebrew}},
+	{"thai", []*unicode.RangeTable{unicode.Thai}},
+}
+
+// IsMixedScript 域名的某一级标签中是否混用了多种文字，例如拉丁字母和西里尔字母，
+// 常见于仿冒域名。域名可以是punycode形式
+func IsMixedScript(host string) bool {
+	if unicodeHost, err := idna.ToUnicode(host); err == nil {
+		host = unicodeHost
+	}
+
+	for _, label := range strings.Split(host, ".") {
+		var scripts []string
+		for _, r := range label {
+			if !unicode.IsLetter(r) {
+				continue
+			}
+			script := scriptOf(r)
+			if script == "" {
+				continue
+			}
+			// 拉丁字母可以与中日韩文字混用，例如日文域名中的英文
+			if (script == "cjk" && contains(scripts, "latin")) || (script == "latin" && contains(scripts, "cjk")) {
+				continue
+			}
+			if !contains(scripts, script) {
+				scripts = append(scripts, script)
+			}
+		}
+		if len(scripts) > 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func scriptOf(r rune) string {
+	for _, group := range scriptGroups {
+		for _, table := range group.tables {
+			if unicode.Is(table, r) {
+				return group.name
+			}
+		}
+	}
+	return ""
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package urlnorm
+
+import (
+	"testing"
+
+	"github.com/aeilang/urlshortener/config"
+)
+
+func TestNormalize(t *testing.T) {
+	n := NewNormalizer(config.NormalizeConfig{SortParams: true})
+
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{"lowercase scheme and host", "HTTPS://Example.COM/Path", "https://example.com/Path"},
+		{"default http port", "http://example.com:80/a", "http://example.com/a"},
+		{"default https port", "https://example.com:443/a", "https://example.com/a"},
+		{"keep other port", "https://example.com:8443/a", "https://example.com:8443/a"},
+		{"idn host", "https://Bücher.example/", "https://xn--bcher-kva.example/"},
+		{"trailing dot", "https://example.com./a", "https://example.com/a"},
+		{"strip tracking params", "https://example.com/?utm_source=x&id=1&fbclid=abc", "https://example.com/?id=1"},
+		{"only tracking params", "https://example.com/a?utm_medium=email", "https://example.com/a"},
+		{"sort params", "https://example.com/?b=2&a=1&b=1", "https://example.com/?a=1&b=2&b=1"},
+		{"keep fragment", "https://example.com/a#Section", "https://example.com/a#Section"},
+		{"ipv6 host", "http://[::1]:80/a", "http://[::1]/a"},
+		{"underscore host", "https://my_host.example.com/", "https://my_host.example.com/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := n.Normalize(tt.raw)
+			if err != nil {
+				t.Fatalf("Normalize(%q) error: %v", tt.raw, err)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeConfig(t *testing.T) {
+	n := NewNormalizer(config.NormalizeConfig{StripParams: []string{"Ref", "x_*"}})
+
+	got, err := n.Normalize("https://example.com/?utm_source=a&ref=b&x_id=c&z=1&y=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 配置替换默认参数，未开启排序时保持原有顺序
+	if want := "https://example.com/?utm_source=a&z=1&y=2"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestNormalizeInvalid(t *testing.T) {
+	n := NewNormalizer(config.NormalizeConfig{})
+
+	invalid := []string{
+		"", "example.com", "/path", "http://",
+		"javascript://example.com/%0aalert(document.domain)",
+		"JavaScript://example.com/",
+		"data://example.com/text/html,<script>alert(1)</script>",
+		"file://localhost/etc/passwd",
+		"ftp://example.com/file",
+	}
+	for _, raw := range invalid {
+		if _, err := n.Normalize(raw); err != ErrInvalidURL {
+			t.Errorf("Normalize(%q) error = %v, want ErrInvalidURL", raw, err)
+		}
+	}
+}
+
+func TestIsMixedScript(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"example.com", false},
+		{"bücher.example", false},
+		{"пример.рф", false},
+		{"例え.jp", false},
+		{"日本abc.jp", false},
+		{"pаypal.com", true}, // 第二个字母是西里尔字母а
+		{"xn--pypal-4ve.com", true},
+		{"αbc.com", true},
+	}
+
+	for _, tt := range tests {
+		if got := IsMixedScript(tt.host); got != tt.want {
+			t.Errorf("IsMixedScript(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package useragent
+
+import (
+	"strings"
+
+	"github.com/aeilang/urlshortener/config"
+)
+
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+	DeviceUnknown = "unknown"
+
+	Other = "other"
+)
+
+// Info User-Agent解析后的设备类型、操作系统和浏览器
+type Info struct {
+	Device  string
+	OS      string
+	Browser string
+	IsBot   bool
+}
+
+type family struct {
+	name     string
+	keywords []string
+}
+
+// 按顺序匹配，靠前的优先，例如Edge和Opera的UA中同样包含Chrome
+var osFamilies = []family{
+	{"iOS", []string{"iphone", "ipad", "ipod"}},
+	{"Android", []string{"android"}},
+	{"Windows", []string{"windows"}},
+	{"ChromeOS", []string{"cros"}},
+	{"macOS", []string{"macintosh", "mac os x"}},
+	{"Linux", []string{"linux", "x11"}},
+}
+
+var browserFamilies = []family{
+	{"Edge", []string{"edg/", "edge/", "edga/", "edgios/"}},
+	{"Opera", []string{"opr/", "opera"}},
+	{"Samsung Internet", []string{"samsungbrowser/"}},
+	{"Firefox", []string{"firefox/", "fxios/"}},
+	{"Chrome", []string{"chrome/", "crios/"}},
+	{"Safari", []string{"safari/"}},
+	{"IE", []string{"msie ", "trident/"}},
+}
+
+// 未配置时使用的机器人特征，包含常见的爬虫和聊天软件的链接预览
+var defaultBotSignatures = []string{
+	"bot", "crawler", "spider", "slurp",
+	"facebookexternalhit", "slack", "whatsapp", "skypeuripreview", "embedly",
+	"curl/", "wget/", "python-requests", "go-http-client", "headlesschrome",
+}
+
+type Parser struct {
+	botSignatures []string
+}
+
+// NewParser 配置的特征会替换默认的机器人特征，匹配时忽略大小写
+func NewParser(cfg config.BotConfig) *Parser {
+	signatures := defaultBotSignatures
+	if len(cfg.Signatures) > 0 {
+		signatures = make([]string, len(cfg.Signatures))
+		for i, signature := range cfg.Signatures {
+			signatures[i] = strings.ToLower(signature)
+		}
+	}
+
+	return &Parser{
+		botSignatures: signatures,
+	}
+}
+
+func (p *Parser) Parse(ua string) Info {
+	// 浏览器总会发送User-Agent，没有的一般是脚本或命令行工具
+	if strings.TrimSpace(ua) == "" {
+		return Info{
+			Device:  DeviceBot,
+			OS:      Other,
+			Browser: Other,
+			IsBot:   true,
+		}
+	}
+
+	lower := strings.ToLower(ua)
+	info := Info{
+		OS:      match(lower, osFamilies),
+		Browser: match(lower, browserFamilies),
+		IsBot:   p.isBot(lower),
+	}
+
+	switch {
+	case info.IsBot:
+		info.Device = DeviceBot
+	case strings.Contains(lower, "ipad") || strings.Contains(lower, "tablet") ||
+		(strings.Contains(lower, "android") && !strings.Contains(lower, "mobile")):
+		info.Device = DeviceTablet
+	case strings.Contains(lower, "mobi") || strings.Contains(lower, "iphone") || strings.Contains(lower, "ipod"):
+		info.Device = DeviceMobile
+	default:
+		info.Device = DeviceDesktop
+	}
+
+	return info
+}
+
+func (p *Parser) isBot(lower string) bool {
+	for _, signature := range p.botSignatures {
+		if strings.Contains(lower, signature) {
+			return true
+		}
+	}
+	return false
+}
+
+func match(lower string, families []family) string {
+	for _, f := range families {
+		for _, keyword := range f.keywords {
+			if strings.Contains(lower, keyword) {
+				return f.name
+			}
+		}
+	}
+	return Other
+}
@@ -0,0 +1,98 @@
+package useragent
+
+import (
+	"testing"
+
+	"github.com/aeilang/urlshortener/config"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		ua   string
+		want Info
+	}{
+		{
+			name: "iphone safari",
+			ua:   "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1",
+			want: Info{Device: DeviceMobile, OS: "iOS", Browser: "Safari"},
+		},
+		{
+			name: "ipad safari",
+			ua:   "Mozilla/5.0 (iPad; CPU OS 16_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.0 Mobile/15E148 Safari/604.1",
+			want: Info{Device: DeviceTablet, OS: "iOS", Browser: "Safari"},
+		},
+		{
+			name: "android phone chrome",
+			ua:   "Mozilla/5.0 (Linux; Android 13; SM-G991B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Mobile Safari/537.36",
+			want: Info{Device: DeviceMobile, OS: "Android", Browser: "Chrome"},
+		},
+		{
+			name: "android tablet",
+			ua:   "Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36",
+			want: Info{Device: DeviceTablet, OS: "Android", Browser: "Chrome"},
+		},
+		{
+			name: "windows edge",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0",
+			want: Info{Device: DeviceDesktop, OS: "Windows", Browser: "Edge"},
+		},
+		{
+			name: "mac safari",
+			ua:   "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15",
+			want: Info{Device: DeviceDesktop, OS: "macOS", Browser: "Safari"},
+		},
+		{
+			name: "linux firefox",
+			ua:   "Mozilla/5.0 (X11; Linux x86_64; rv:120.0) Gecko/20100101 Firefox/120.0",
+			want: Info{Device: DeviceDesktop, OS: "Linux", Browser: "Firefox"},
+		},
+		{
+			name: "googlebot",
+			ua:   "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			want: Info{Device: DeviceBot, OS: Other, Browser: Other, IsBot: true},
+		},
+		{
+			name: "slack unfurler",
+			ua:   "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)",
+			want: Info{Device: DeviceBot, OS: Other, Browser: Other, IsBot: true},
+		},
+		{
+			name: "whatsapp preview",
+			ua:   "WhatsApp/2.23.20.0",
+			want: Info{Device: DeviceBot, OS: Other, Browser: Other, IsBot: true},
+		},
+		{
+			name: "empty",
+			ua:   "",
+			want: Info{Device: DeviceBot, OS: Other, Browser: Other, IsBot: true},
+		},
+		{
+			name: "blank",
+			ua:   "  ",
+			want: Info{Device: DeviceBot, OS: Other, Browser: Other, IsBot: true},
+		},
+	}
+
+	parser := NewParser(config.BotConfig{})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parser.Parse(tt.ua); got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConfiguredSignatures(t *testing.T) {
+	parser := NewParser(config.BotConfig{Signatures: []string{"MyMonitor"}})
+
+	if !parser.Parse("mymonitor/1.0").IsBot {
+		t.Error("configured signature must be detected as bot")
+	}
+
+	if parser.Parse("Mozilla/5.0 (compatible; Googlebot/2.1)").IsBot {
+		t.Error("configured signatures must replace the defaults")
+	}
+}